@@ -0,0 +1,266 @@
+package lru
+
+// Arc implements a non-thread-safe adaptive replacement cache. t1 keeps
+// entries seen once recently and t2 entries seen at least twice, b1 and b2
+// remember the keys recently evicted from them. A hit on a ghost key moves
+// the target size p of t1, so the cache tunes itself between recency and
+// frequency.
+type Arc[k comparable, v any] struct {
+	capacity int
+	p        int
+
+	t1, t2         *List[k, v]
+	t1Hash, t2Hash map[k]*Node[k, v]
+	b1, b2         *List[k, struct{}]
+	b1Hash, b2Hash map[k]*Node[k, struct{}]
+
+	onEvict EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*Arc[int, int])(nil)
+
+// NewArc returns an arc holding at most capacity entries, capacity is at
+// least 1
+func NewArc[k comparable, v any](capacity int, onEvict EvictFunc[k, v]) *Arc[k, v] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	arc := &Arc[k, v]{
+		capacity: capacity,
+		onEvict:  onEvict,
+	}
+	arc.reset()
+	return arc
+}
+
+func (arc *Arc[k, v]) reset() {
+	arc.p = 0
+	arc.t1 = NewList[k, v]()
+	arc.t2 = NewList[k, v]()
+	arc.b1 = NewList[k, struct{}]()
+	arc.b2 = NewList[k, struct{}]()
+	arc.t1Hash = make(map[k]*Node[k, v])
+	arc.t2Hash = make(map[k]*Node[k, v])
+	arc.b1Hash = make(map[k]*Node[k, struct{}])
+	arc.b2Hash = make(map[k]*Node[k, struct{}])
+}
+
+func (arc *Arc[k, v]) Add(key k, value v) (overwrite bool) {
+	if node, ok := arc.t1Hash[key]; ok {
+		node.value = value
+		arc.promote(node)
+		return true
+	}
+	if node, ok := arc.t2Hash[key]; ok {
+		node.value = value
+		arc.t2.MoveToFront(node)
+		return true
+	}
+
+	if ghost, ok := arc.b1Hash[key]; ok {
+		// recency is undersized, grow t1 target
+		delta := 1
+		if arc.b2.Len() > arc.b1.Len() {
+			delta = arc.b2.Len() / arc.b1.Len()
+		}
+		arc.p += delta
+		if arc.p > arc.capacity {
+			arc.p = arc.capacity
+		}
+		arc.removeGhost(arc.b1, arc.b1Hash, ghost)
+		if arc.Len() >= arc.capacity {
+			arc.replace(false)
+		}
+		arc.t2Hash[key] = arc.t2.Prepend(key, value)
+		return false
+	}
+	if ghost, ok := arc.b2Hash[key]; ok {
+		// frequency is undersized, shrink t1 target
+		delta := 1
+		if arc.b1.Len() > arc.b2.Len() {
+			delta = arc.b1.Len() / arc.b2.Len()
+		}
+		arc.p -= delta
+		if arc.p < 0 {
+			arc.p = 0
+		}
+		arc.removeGhost(arc.b2, arc.b2Hash, ghost)
+		if arc.Len() >= arc.capacity {
+			arc.replace(true)
+		}
+		arc.t2Hash[key] = arc.t2.Prepend(key, value)
+		return false
+	}
+
+	if arc.Len() >= arc.capacity {
+		arc.replace(false)
+	}
+	if arc.b1.Len() > arc.capacity-arc.p {
+		arc.removeGhost(arc.b1, arc.b1Hash, arc.b1.Back())
+	}
+	if arc.b2.Len() > arc.p {
+		arc.removeGhost(arc.b2, arc.b2Hash, arc.b2.Back())
+	}
+	arc.t1Hash[key] = arc.t1.Prepend(key, value)
+	return false
+}
+
+func (arc *Arc[k, v]) Get(key k) (value v, exist bool) {
+	if node, ok := arc.t1Hash[key]; ok {
+		arc.promote(node)
+		return node.value, true
+	}
+	if node, ok := arc.t2Hash[key]; ok {
+		arc.t2.MoveToFront(node)
+		return node.value, true
+	}
+	return value, false
+}
+
+// Peek returns the value of key without touching the lists
+func (arc *Arc[k, v]) Peek(key k) (value v, exist bool) {
+	if node, ok := arc.t1Hash[key]; ok {
+		return node.value, true
+	}
+	if node, ok := arc.t2Hash[key]; ok {
+		return node.value, true
+	}
+	return value, false
+}
+
+func (arc *Arc[k, v]) Remove(key k) (exist bool) {
+	if ghost, ok := arc.b1Hash[key]; ok {
+		arc.removeGhost(arc.b1, arc.b1Hash, ghost)
+	}
+	if ghost, ok := arc.b2Hash[key]; ok {
+		arc.removeGhost(arc.b2, arc.b2Hash, ghost)
+	}
+	if node, ok := arc.t1Hash[key]; ok {
+		arc.removeNode(arc.t1, arc.t1Hash, node, EvictReasonRemoved)
+		return true
+	}
+	if node, ok := arc.t2Hash[key]; ok {
+		arc.removeNode(arc.t2, arc.t2Hash, node, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest pops the entry arc would replace next, without remembering
+// it in a ghost list
+func (arc *Arc[k, v]) RemoveOldest() (key k, value v) {
+	list, hash := arc.victimList()
+	node := list.Back()
+	if node == nil {
+		return
+	}
+	arc.removeNode(list, hash, node, EvictReasonRemoved)
+	return node.key, node.value
+}
+
+func (arc *Arc[k, v]) Clear() {
+	t1, t2 := arc.t1, arc.t2
+	arc.reset()
+	if arc.onEvict != nil {
+		for _, list := range []*List[k, v]{t1, t2} {
+			list.Iterate(func(key k, value v) bool {
+				arc.onEvict(key, value, EvictReasonCleared)
+				return false
+			})
+		}
+	}
+}
+
+func (arc *Arc[k, v]) Len() int {
+	return arc.t1.Len() + arc.t2.Len()
+}
+
+func (arc *Arc[k, v]) Cap() int {
+	return arc.capacity
+}
+
+// Iterate walks the frequent entries then the recent ones, each from the
+// most recently used
+func (arc *Arc[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	stop := false
+	wrapper := func(key k, value v) bool {
+		stop = iterateFunc(key, value)
+		return stop
+	}
+	arc.t2.Iterate(wrapper)
+	if !stop {
+		arc.t1.Iterate(wrapper)
+	}
+}
+
+// IterateList walks in replacement order
+func (arc *Arc[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	stop := false
+	wrapper := func(key k, value v) bool {
+		stop = iterateFunc(key, value)
+		return stop
+	}
+	first, _ := arc.victimList()
+	second := arc.t2
+	if first == arc.t2 {
+		second = arc.t1
+	}
+	first.IterateReverse(wrapper)
+	if !stop {
+		second.IterateReverse(wrapper)
+	}
+}
+
+// promote moves a node hit in t1 to the head of t2
+func (arc *Arc[k, v]) promote(node *Node[k, v]) {
+	arc.t1.Remove(node)
+	delete(arc.t1Hash, node.key)
+	arc.t2.pushFront(node)
+	arc.t2Hash[node.key] = node
+}
+
+// victimList returns the list replace evicts from when no ghost hit drives it
+func (arc *Arc[k, v]) victimList() (*List[k, v], map[k]*Node[k, v]) {
+	if arc.t1.Len() > 0 && (arc.t1.Len() > arc.p || arc.t2.Len() == 0) {
+		return arc.t1, arc.t1Hash
+	}
+	return arc.t2, arc.t2Hash
+}
+
+// replace evicts one entry and remembers its key in the matching ghost list
+func (arc *Arc[k, v]) replace(b2Hit bool) {
+	t1Len := arc.t1.Len()
+	if t1Len > 0 && (t1Len > arc.p || (t1Len == arc.p && b2Hit) || arc.t2.Len() == 0) {
+		node := arc.t1.Back()
+		arc.removeNode(arc.t1, arc.t1Hash, node, EvictReasonCapacity)
+		arc.addGhost(arc.b1, arc.b1Hash, node.key)
+		return
+	}
+	if node := arc.t2.Back(); node != nil {
+		arc.removeNode(arc.t2, arc.t2Hash, node, EvictReasonCapacity)
+		arc.addGhost(arc.b2, arc.b2Hash, node.key)
+	}
+}
+
+func (arc *Arc[k, v]) addGhost(list *List[k, struct{}], hash map[k]*Node[k, struct{}], key k) {
+	hash[key] = list.Prepend(key, struct{}{})
+	if list.Len() > arc.capacity {
+		arc.removeGhost(list, hash, list.Back())
+	}
+}
+
+func (arc *Arc[k, v]) removeGhost(list *List[k, struct{}], hash map[k]*Node[k, struct{}], node *Node[k, struct{}]) {
+	if node == nil {
+		return
+	}
+	list.Remove(node)
+	delete(hash, node.key)
+}
+
+func (arc *Arc[k, v]) removeNode(list *List[k, v], hash map[k]*Node[k, v], node *Node[k, v], reason EvictReason) {
+	list.Remove(node)
+	delete(hash, node.key)
+	if arc.onEvict != nil {
+		arc.onEvict(node.key, node.value, reason)
+	}
+}
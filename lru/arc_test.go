@@ -0,0 +1,65 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ArcTestSuite struct {
+	suite.Suite
+	arc *Arc[int, int]
+}
+
+func (s *ArcTestSuite) SetupTest() {
+	s.arc = NewArc[int, int](4, nil)
+}
+
+func (s *ArcTestSuite) TestPromote() {
+	s.arc.Add(1, 1)
+	s.arc.Add(2, 2)
+	s.Equal(2, s.arc.t1.Len())
+
+	s.arc.Get(1)
+	s.Equal(1, s.arc.t1.Len())
+	s.Equal(1, s.arc.t2.Len())
+	s.True(s.arc.Add(2, 20))
+	s.Equal(0, s.arc.t1.Len())
+	s.Equal(2, s.arc.t2.Len())
+}
+
+func (s *ArcTestSuite) TestGhostAdapt() {
+	for i := 0; i < 4; i++ {
+		s.arc.Add(i, i)
+	}
+	s.arc.Add(4, 4)
+	_, ok := s.arc.Peek(0)
+	s.False(ok)
+	s.Equal(1, s.arc.b1.Len())
+
+	// a recent key coming back grows the recency target
+	s.arc.Add(0, 0)
+	s.Equal(1, s.arc.p)
+	s.Equal(1, s.arc.t2.Len())
+	s.Equal(4, s.arc.Len())
+}
+
+func (s *ArcTestSuite) TestScanResistant() {
+	for i := 0; i < 2; i++ {
+		s.arc.Add(i, i)
+		s.arc.Get(i)
+	}
+	for i := 100; i < 200; i++ {
+		s.arc.Add(i, i)
+	}
+	for i := 0; i < 2; i++ {
+		_, ok := s.arc.Get(i)
+		s.True(ok)
+	}
+	s.Equal(4, s.arc.Len())
+	s.LessOrEqual(s.arc.b1.Len()+s.arc.b2.Len(), 2*s.arc.Cap())
+}
+
+func TestArcTestSuite(t *testing.T) {
+	suite.Run(t, new(ArcTestSuite))
+}
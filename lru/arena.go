@@ -0,0 +1,405 @@
+package lru
+
+import (
+	"math"
+	"time"
+)
+
+// arenaNode links entries by index into ArenaList.nodes, index 0 is the
+// sentinel so a zero link means the list end
+type arenaNode[k comparable, v any] struct {
+	pre, nxt int32
+	key      k
+	value    v
+	// expire is the unix nano deadline of the entry, 0 means never
+	expire int64
+}
+
+// ArenaList is a doubly linked list whose nodes live in one slice and link
+// by int32 index, so the links hold no pointer for the gc to scan. With
+// pointer-free key and value types, e.g. integers but not strings, the gc
+// skips the slice altogether. Nodes are addressed by the index returned from Prepend and Append, removed
+// nodes are recycled through a free list.
+type ArenaList[k comparable, v any] struct {
+	nodes []arenaNode[k, v]
+	// free chains recycled nodes through nxt, 0 means none
+	free int32
+	size int
+}
+
+// NewArenaList returns an empty list, sizeHint preallocates nodes
+func NewArenaList[k comparable, v any](sizeHint int) *ArenaList[k, v] {
+	if sizeHint < 0 {
+		sizeHint = 0
+	}
+	return &ArenaList[k, v]{
+		nodes: make([]arenaNode[k, v], 1, sizeHint+1),
+	}
+}
+
+// alloc takes a node from the free list or grows the arena
+func (list *ArenaList[k, v]) alloc(key k, value v) int32 {
+	index := list.free
+	if index != 0 {
+		list.free = list.nodes[index].nxt
+	} else {
+		if len(list.nodes) == math.MaxInt32 {
+			panic("lru: arena list is full")
+		}
+		index = int32(len(list.nodes))
+		list.nodes = append(list.nodes, arenaNode[k, v]{})
+	}
+	list.nodes[index] = arenaNode[k, v]{key: key, value: value}
+	list.size += 1
+	return index
+}
+
+func (list *ArenaList[k, v]) link(index, pre int32) {
+	nodes := list.nodes
+	nxt := nodes[pre].nxt
+	nodes[index].pre = pre
+	nodes[index].nxt = nxt
+	nodes[nxt].pre = index
+	nodes[pre].nxt = index
+}
+
+func (list *ArenaList[k, v]) unlink(index int32) {
+	nodes := list.nodes
+	nodes[nodes[index].pre].nxt = nodes[index].nxt
+	nodes[nodes[index].nxt].pre = nodes[index].pre
+}
+
+func (list *ArenaList[k, v]) Prepend(key k, value v) int32 {
+	index := list.alloc(key, value)
+	list.link(index, 0)
+	return index
+}
+
+func (list *ArenaList[k, v]) Append(key k, value v) int32 {
+	index := list.alloc(key, value)
+	list.link(index, list.nodes[0].pre)
+	return index
+}
+
+func (list *ArenaList[k, v]) MoveToFront(index int32) {
+	if list.nodes[0].nxt == index {
+		return
+	}
+	list.unlink(index)
+	list.link(index, 0)
+}
+
+// Remove unlinks the node and recycles its index, the key and value are
+// zeroed so they can be collected
+func (list *ArenaList[k, v]) Remove(index int32) {
+	list.unlink(index)
+	list.nodes[index] = arenaNode[k, v]{nxt: list.free}
+	list.free = index
+	list.size -= 1
+}
+
+// Back returns the index of the oldest node, or 0 if list is empty
+func (list *ArenaList[k, v]) Back() int32 {
+	return list.nodes[0].pre
+}
+
+// Key returns the key of the node at index
+func (list *ArenaList[k, v]) Key(index int32) k {
+	return list.nodes[index].key
+}
+
+// Value returns the value of the node at index
+func (list *ArenaList[k, v]) Value(index int32) v {
+	return list.nodes[index].value
+}
+
+func (list *ArenaList[k, v]) Len() int {
+	return list.size
+}
+
+func (list *ArenaList[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	list.walk(false, func(index int32) bool {
+		return iterateFunc(list.nodes[index].key, list.nodes[index].value)
+	})
+}
+
+// IterateReverse walks the list from tail to head
+func (list *ArenaList[k, v]) IterateReverse(iterateFunc IterateFunc[k, v]) {
+	list.walk(true, func(index int32) bool {
+		return iterateFunc(list.nodes[index].key, list.nodes[index].value)
+	})
+}
+
+// walk visits node indexes from head to tail, or from tail to head if
+// reverse. visit may remove the visited node.
+func (list *ArenaList[k, v]) walk(reverse bool, visit func(index int32) (stop bool)) {
+	for index := list.next(0, reverse); index != 0; {
+		next := list.next(index, reverse)
+		if visit(index) {
+			return
+		}
+		index = next
+	}
+}
+
+func (list *ArenaList[k, v]) next(index int32, reverse bool) int32 {
+	if reverse {
+		return list.nodes[index].pre
+	}
+	return list.nodes[index].nxt
+}
+
+// ArenaLru is a non-thread-safe lru cache on an ArenaList, it behaves like
+// Lru but allocates nothing per entry once the arena has grown. With
+// pointer-free key and value types this keeps gc scan time flat for caches
+// with millions of entries, keys or values holding pointers such as strings
+// are still scanned one slot at a time. Tags, pinning and snapshots are
+// only provided by Lru.
+type ArenaLru[k comparable, v any] struct {
+	list     *ArenaList[k, v]
+	hash     map[k]int32
+	capacity int
+	onEvict  EvictFunc[k, v]
+	ttl      time.Duration
+	clock    Clock
+	stats    *statsCounter
+}
+
+var _ ILru[int, int] = (*ArenaLru[int, int])(nil)
+
+// NewArenaLru returns a lru holding at most capacity entries, the arena and
+// the index are sized for capacity up front. capacity <= 0 means unbounded,
+// onEvict is optional.
+func NewArenaLru[k comparable, v any](capacity int, onEvict EvictFunc[k, v]) *ArenaLru[k, v] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &ArenaLru[k, v]{
+		list:     NewArenaList[k, v](capacity),
+		hash:     make(map[k]int32, capacity),
+		capacity: capacity,
+		onEvict:  onEvict,
+		clock:    SystemClock,
+		stats:    &statsCounter{},
+	}
+}
+
+// Add inserts or replaces the value of key and marks it most recently used,
+// the entry gets the default ttl if any
+func (lru *ArenaLru[k, v]) Add(key k, value v) (overwrite bool) {
+	return lru.add(key, value, lru.ttl)
+}
+
+// AddWithTTL works as Add but the entry expires after ttl, ttl <= 0 means
+// never
+func (lru *ArenaLru[k, v]) AddWithTTL(key k, value v, ttl time.Duration) (overwrite bool) {
+	return lru.add(key, value, ttl)
+}
+
+func (lru *ArenaLru[k, v]) add(key k, value v, ttl time.Duration) (overwrite bool) {
+	if index, ok := lru.hash[key]; ok {
+		if !lru.expired(index) {
+			node := &lru.list.nodes[index]
+			node.value = value
+			node.expire = lru.deadline(ttl)
+			lru.list.MoveToFront(index)
+			lru.stats.update()
+			return true
+		}
+		lru.removeNode(index, EvictReasonExpired)
+	}
+	lru.stats.insert()
+	// evicting first lets the new entry reuse the freed node, so the arena
+	// never grows past capacity
+	lru.trim(1)
+	index := lru.list.Prepend(key, value)
+	lru.list.nodes[index].expire = lru.deadline(ttl)
+	lru.hash[key] = index
+	return false
+}
+
+// trim evicts from the list tail until reserve more entries fit
+func (lru *ArenaLru[k, v]) trim(reserve int) (evicted int) {
+	if lru.capacity <= 0 {
+		return 0
+	}
+	for lru.list.Len() > 0 && lru.list.Len()+reserve > lru.capacity {
+		index := lru.list.Back()
+		if lru.expired(index) {
+			lru.removeNode(index, EvictReasonExpired)
+		} else {
+			lru.removeNode(index, EvictReasonCapacity)
+		}
+		evicted++
+	}
+	return evicted
+}
+
+func (lru *ArenaLru[k, v]) removeNode(index int32, reason EvictReason) {
+	node := lru.list.nodes[index]
+	lru.list.Remove(index)
+	delete(lru.hash, node.key)
+	lru.stats.evict(reason, 1)
+	if lru.onEvict != nil {
+		lru.onEvict(node.key, node.value, reason)
+	}
+}
+
+// Get returns the value of key and marks it most recently used, an expired
+// entry is removed and reported as missing
+func (lru *ArenaLru[k, v]) Get(key k) (value v, exist bool) {
+	if index, ok := lru.hash[key]; ok {
+		if lru.expired(index) {
+			lru.removeNode(index, EvictReasonExpired)
+			lru.stats.miss()
+			return value, false
+		}
+		lru.list.MoveToFront(index)
+		lru.stats.hit()
+		return lru.list.nodes[index].value, true
+	}
+	lru.stats.miss()
+	return value, false
+}
+
+// Peek returns the value of key without updating its recency
+func (lru *ArenaLru[k, v]) Peek(key k) (value v, exist bool) {
+	if index, ok := lru.hash[key]; ok && !lru.expired(index) {
+		return lru.list.nodes[index].value, true
+	}
+	return value, false
+}
+
+// Contains checks key without updating its recency
+func (lru *ArenaLru[k, v]) Contains(key k) bool {
+	index, ok := lru.hash[key]
+	return ok && !lru.expired(index)
+}
+
+func (lru *ArenaLru[k, v]) Remove(key k) (exist bool) {
+	if index, ok := lru.hash[key]; ok {
+		lru.removeNode(index, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest pops the least recently used entry, zero values are
+// returned if lru is empty. Expired entries met on the way are dropped.
+func (lru *ArenaLru[k, v]) RemoveOldest() (key k, value v) {
+	for index := lru.list.Back(); index != 0; index = lru.list.Back() {
+		if lru.expired(index) {
+			lru.removeNode(index, EvictReasonExpired)
+			continue
+		}
+		node := lru.list.nodes[index]
+		lru.removeNode(index, EvictReasonRemoved)
+		return node.key, node.value
+	}
+	return
+}
+
+// Clear drops every entry, a new arena is sized for capacity
+func (lru *ArenaLru[k, v]) Clear() {
+	list := lru.list
+	lru.hash = make(map[k]int32, lru.capacity)
+	lru.list = NewArenaList[k, v](lru.capacity)
+	lru.stats.evict(EvictReasonCleared, list.Len())
+	if lru.onEvict != nil {
+		list.Iterate(func(key k, value v) bool {
+			lru.onEvict(key, value, EvictReasonCleared)
+			return false
+		})
+	}
+}
+
+// Cap returns the max entry count, 0 means unbounded
+func (lru *ArenaLru[k, v]) Cap() int {
+	return lru.capacity
+}
+
+// Len counts entries including the expired ones not dropped yet
+func (lru *ArenaLru[k, v]) Len() int {
+	return lru.list.Len()
+}
+
+// Resize changes the max entry count and evicts from the tail right away
+// when shrinking. capacity <= 0 means unbounded.
+func (lru *ArenaLru[k, v]) Resize(capacity int) (evicted int) {
+	if capacity < 0 {
+		capacity = 0
+	}
+	lru.capacity = capacity
+	return lru.trim(0)
+}
+
+// Iterate walks from most to least recently used, skipping expired entries
+func (lru *ArenaLru[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	lru.iterate(false, iterateFunc)
+}
+
+// IterateList walks from least to most recently used, skipping expired entries
+func (lru *ArenaLru[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	lru.iterate(true, iterateFunc)
+}
+
+func (lru *ArenaLru[k, v]) iterate(reverse bool, iterateFunc IterateFunc[k, v]) {
+	now := lru.clock.Now().UnixNano()
+	lru.list.walk(reverse, func(index int32) bool {
+		node := &lru.list.nodes[index]
+		if node.expire != 0 && now >= node.expire {
+			return false
+		}
+		return iterateFunc(node.key, node.value)
+	})
+}
+
+// SetDefaultTTL sets the ttl given to entries by Add, ttl <= 0 means entries
+// never expire
+func (lru *ArenaLru[k, v]) SetDefaultTTL(ttl time.Duration) {
+	lru.ttl = ttl
+}
+
+// SetClock replaces the clock used for expiry, nil restores SystemClock
+func (lru *ArenaLru[k, v]) SetClock(clock Clock) {
+	if clock == nil {
+		clock = SystemClock
+	}
+	lru.clock = clock
+}
+
+// RemoveExpired drops every expired entry and returns how many were dropped
+func (lru *ArenaLru[k, v]) RemoveExpired() int {
+	now := lru.clock.Now().UnixNano()
+	count := 0
+	lru.list.walk(false, func(index int32) bool {
+		if expire := lru.list.nodes[index].expire; expire != 0 && now >= expire {
+			lru.removeNode(index, EvictReasonExpired)
+			count++
+		}
+		return false
+	})
+	return count
+}
+
+func (lru *ArenaLru[k, v]) deadline(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return lru.clock.Now().Add(ttl).UnixNano()
+}
+
+func (lru *ArenaLru[k, v]) expired(index int32) bool {
+	expire := lru.list.nodes[index].expire
+	return expire != 0 && lru.clock.Now().UnixNano() >= expire
+}
+
+func (lru *ArenaLru[k, v]) Stats() Stats {
+	stats := lru.stats.snapshot()
+	stats.Size = lru.Len()
+	return stats
+}
+
+func (lru *ArenaLru[k, v]) ResetStats() {
+	lru.stats.reset()
+}
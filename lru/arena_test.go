@@ -0,0 +1,120 @@
+package lru
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ArenaLruTestSuite struct {
+	suite.Suite
+	lru *ArenaLru[int, string]
+}
+
+func (s *ArenaLruTestSuite) SetupTest() {
+	s.lru = NewArenaLru[int, string](4, nil)
+}
+
+func (s *ArenaLruTestSuite) keys() []int {
+	var keys []int
+	s.lru.Iterate(func(key int, value string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	return keys
+}
+
+func (s *ArenaLruTestSuite) TestOrder() {
+	for i := 0; i < 4; i++ {
+		s.lru.Add(i, fmt.Sprintf("I'm %v", i))
+	}
+	s.True(s.lru.Add(1, "updated"))
+	s.Equal([]int{1, 3, 2, 0}, s.keys())
+
+	s.lru.Add(4, "I'm 4")
+	s.Equal([]int{4, 1, 3, 2}, s.keys())
+	value, ok := s.lru.Peek(1)
+	s.True(ok)
+	s.Equal("updated", value)
+}
+
+func (s *ArenaLruTestSuite) TestFreeList() {
+	for i := 0; i < 64; i++ {
+		s.lru.Add(i, fmt.Sprintf("I'm %v", i))
+	}
+	// evicted nodes are recycled, the arena never outgrows capacity
+	s.Equal(5, len(s.lru.list.nodes))
+	s.True(s.lru.Remove(62))
+	s.Equal("", s.lru.list.nodes[s.lru.list.free].value)
+	s.lru.Add(100, "I'm 100")
+	s.Equal(5, len(s.lru.list.nodes))
+	s.Equal([]int{100, 63, 61, 60}, s.keys())
+}
+
+func (s *ArenaLruTestSuite) TestTTL() {
+	clock := newFakeClock()
+	s.lru.SetClock(clock)
+	s.lru.AddWithTTL(1, "one", time.Second)
+	s.lru.Add(2, "two")
+	clock.Advance(time.Second)
+	s.False(s.lru.Contains(1))
+	s.Equal(1, s.lru.RemoveExpired())
+	s.Equal(1, s.lru.Len())
+
+	s.Equal(uint64(1), s.lru.Stats().Evictions[EvictReasonExpired])
+	s.Equal(0, s.lru.Resize(1))
+	s.Equal(1, s.lru.Cap())
+}
+
+func TestArenaLruTestSuite(t *testing.T) {
+	suite.Run(t, new(ArenaLruTestSuite))
+}
+
+const gcBenchmarkEntries = 1 << 20
+
+// benchmarkGC fills a large cache and measures full gc cycles while it is
+// alive, the per entry nodes of Lru must be scanned on every cycle
+func benchmarkGC(b *testing.B, cache ILru[int, int]) {
+	for i := 0; i < gcBenchmarkEntries; i++ {
+		cache.Add(i, i)
+	}
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	pause := stats.PauseTotalNs
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runtime.GC()
+	}
+	b.StopTimer()
+	runtime.ReadMemStats(&stats)
+	b.ReportMetric(float64(stats.PauseTotalNs-pause)/float64(b.N), "pause-ns/gc")
+	runtime.KeepAlive(cache)
+}
+
+func BenchmarkLruGC(b *testing.B) {
+	benchmarkGC(b, NewLruWithCapacity[int, int](gcBenchmarkEntries, nil))
+}
+
+func BenchmarkArenaLruGC(b *testing.B) {
+	benchmarkGC(b, NewArenaLru[int, int](gcBenchmarkEntries, nil))
+}
+
+// benchmarkChurn adds keys past capacity so every Add evicts
+func benchmarkChurn(b *testing.B, cache ILru[int, int]) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cache.Add(i, i)
+	}
+}
+
+func BenchmarkLruChurn(b *testing.B) {
+	benchmarkChurn(b, NewLruWithCapacity[int, int](benchmarkKeys, nil))
+}
+
+func BenchmarkArenaLruChurn(b *testing.B) {
+	benchmarkChurn(b, NewArenaLru[int, int](benchmarkKeys, nil))
+}
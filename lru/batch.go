@@ -0,0 +1,250 @@
+package lru
+
+import "context"
+
+// Entry is a key value pair of the batch operations
+type Entry[k comparable, v any] struct {
+	Key   k
+	Value v
+}
+
+// GetMany looks keys up as Get does, found and missing keep the order of
+// keys
+func (lru *Lru[k, v]) GetMany(keys []k) (found []Entry[k, v], missing []k) {
+	found = make([]Entry[k, v], 0, len(keys))
+	for _, key := range keys {
+		if value, ok := lru.Get(key); ok {
+			found = append(found, Entry[k, v]{key, value})
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// AddMany adds entries in order as Add does and returns how many replaced a
+// cached value
+func (lru *Lru[k, v]) AddMany(entries []Entry[k, v]) (overwritten int) {
+	for _, entry := range entries {
+		if lru.Add(entry.Key, entry.Value) {
+			overwritten++
+		}
+	}
+	return overwritten
+}
+
+// RemoveMany removes keys and returns how many were cached
+func (lru *Lru[k, v]) RemoveMany(keys []k) (removed int) {
+	for _, key := range keys {
+		if lru.Remove(key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// GetMany takes the lock once for all keys
+func (cache *SyncLru[k, v]) GetMany(keys []k) (found []Entry[k, v], missing []k) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.GetMany(keys)
+}
+
+// AddMany takes the lock once for all entries
+func (cache *SyncLru[k, v]) AddMany(entries []Entry[k, v]) (overwritten int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.AddMany(entries)
+}
+
+// RemoveMany takes the lock once for all keys
+func (cache *SyncLru[k, v]) RemoveMany(keys []k) (removed int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.RemoveMany(keys)
+}
+
+// group splits positions 0..n-1 by the shard of key(i)
+func (cache *ShardedLru[k, v]) group(n int, key func(i int) k) [][]int {
+	groups := make([][]int, len(cache.shards))
+	for i := 0; i < n; i++ {
+		index := cache.shardIndex(key(i))
+		groups[index] = append(groups[index], i)
+	}
+	return groups
+}
+
+// GetMany takes the lock of every involved shard once, found and missing
+// keep the order of keys
+func (cache *ShardedLru[k, v]) GetMany(keys []k) (found []Entry[k, v], missing []k) {
+	values := make([]v, len(keys))
+	exist := make([]bool, len(keys))
+	groups := cache.group(len(keys), func(i int) k { return keys[i] })
+	for index, positions := range groups {
+		if len(positions) == 0 {
+			continue
+		}
+		shard := cache.shards[index]
+		shard.lock.Lock()
+		for _, i := range positions {
+			values[i], exist[i] = shard.lru.Get(keys[i])
+		}
+		shard.lock.Unlock()
+	}
+	for i, key := range keys {
+		if exist[i] {
+			found = append(found, Entry[k, v]{key, values[i]})
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// AddMany takes the lock of every involved shard once, entries of a shard
+// are added in order
+func (cache *ShardedLru[k, v]) AddMany(entries []Entry[k, v]) (overwritten int) {
+	groups := cache.group(len(entries), func(i int) k { return entries[i].Key })
+	for index, positions := range groups {
+		if len(positions) == 0 {
+			continue
+		}
+		shard := cache.shards[index]
+		shard.lock.Lock()
+		for _, i := range positions {
+			if shard.lru.Add(entries[i].Key, entries[i].Value) {
+				overwritten++
+			}
+		}
+		shard.lock.Unlock()
+	}
+	return overwritten
+}
+
+// RemoveMany takes the lock of every involved shard once
+func (cache *ShardedLru[k, v]) RemoveMany(keys []k) (removed int) {
+	groups := cache.group(len(keys), func(i int) k { return keys[i] })
+	for index, positions := range groups {
+		if len(positions) == 0 {
+			continue
+		}
+		shard := cache.shards[index]
+		shard.lock.Lock()
+		for _, i := range positions {
+			if shard.lru.Remove(keys[i]) {
+				removed++
+			}
+		}
+		shard.lock.Unlock()
+	}
+	return removed
+}
+
+// BatchLoader fetches the values of several missing keys at once, keys
+// absent from the returned map are reported missing
+type BatchLoader[k comparable, v any] func(ctx context.Context, keys []k) (map[k]v, error)
+
+// SetBatchLoader sets the loader used by GetOrLoadMany, without one it
+// loads the keys one by one with the Loader. Not safe to call concurrently
+// with GetOrLoadMany.
+func (cache *LoadingCache[k, v]) SetBatchLoader(batchLoader BatchLoader[k, v]) {
+	cache.batchLoader = batchLoader
+}
+
+// GetOrLoadMany returns the cached values of keys and loads the others with
+// a single call to the batch loader, found and missing keep the order of
+// keys. A key repeated in keys is loaded once. Keys with a cached error are
+// reported missing without being loaded.
+// A batch load error is returned along with the cached values and is not
+// cached. Batch loads are not shared with concurrent GetOrLoad calls, a key
+// may be loaded by both.
+func (cache *LoadingCache[k, v]) GetOrLoadMany(ctx context.Context, keys []k) (found []Entry[k, v], missing []k, err error) {
+	var refresh []k
+	cache.cache.lock.Lock()
+	found, missing = cache.cache.lru.GetMany(keys)
+	for _, entry := range found {
+		if cache.refreshDue(entry.Key) {
+			refresh = append(refresh, entry.Key)
+		}
+	}
+	cache.cache.lock.Unlock()
+	for _, key := range refresh {
+		go cache.refresh(detachedContext{ctx}, key)
+	}
+
+	var load []k
+	seen := make(map[k]struct{}, len(missing))
+	for _, key := range missing {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if cache.negativeTTL > 0 && cache.errors.Contains(key) {
+			continue
+		}
+		load = append(load, key)
+	}
+	if len(load) == 0 {
+		return found, missing, nil
+	}
+	var loaded map[k]v
+	if cache.batchLoader != nil {
+		loaded, err = cache.batchLoader(detachedContext{ctx}, load)
+		if err != nil {
+			return found, missing, err
+		}
+		now := cache.clock.Now().UnixNano()
+		cache.cache.lock.Lock()
+		for _, key := range load {
+			if value, ok := loaded[key]; ok {
+				cache.cache.lru.Add(key, value)
+				cache.loadedAt[key] = now
+			}
+		}
+		cache.cache.lock.Unlock()
+		for key := range loaded {
+			cache.errors.Remove(key)
+		}
+	} else {
+		loaded, err = cache.loadEach(ctx, load)
+	}
+	found, missing = partition(keys, found, loaded)
+	return found, missing, err
+}
+
+// loadEach loads keys one by one through GetOrLoad, keys failing to load
+// are left out and the first error is returned
+func (cache *LoadingCache[k, v]) loadEach(ctx context.Context, keys []k) (loaded map[k]v, err error) {
+	loaded = make(map[k]v, len(keys))
+	for _, key := range keys {
+		value, loadErr := cache.GetOrLoad(ctx, key)
+		if loadErr != nil {
+			if err == nil {
+				err = loadErr
+			}
+			continue
+		}
+		loaded[key] = value
+	}
+	return loaded, err
+}
+
+// partition splits keys between the cached and loaded values and the
+// missing ones
+func partition[k comparable, v any](keys []k, cached []Entry[k, v], loaded map[k]v) (found []Entry[k, v], missing []k) {
+	values := make(map[k]v, len(cached)+len(loaded))
+	for _, entry := range cached {
+		values[entry.Key] = entry.Value
+	}
+	for key, value := range loaded {
+		values[key] = value
+	}
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			found = append(found, Entry[k, v]{key, value})
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
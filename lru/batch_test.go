@@ -0,0 +1,164 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchTestSuite struct {
+	suite.Suite
+}
+
+func batchEntries(keys ...int) []Entry[int, string] {
+	entries := make([]Entry[int, string], len(keys))
+	for i, key := range keys {
+		entries[i] = Entry[int, string]{key, fmt.Sprintf("value-%v", key)}
+	}
+	return entries
+}
+
+type batchCache interface {
+	GetMany(keys []int) (found []Entry[int, string], missing []int)
+	AddMany(entries []Entry[int, string]) (overwritten int)
+	RemoveMany(keys []int) (removed int)
+	Len() int
+}
+
+func (s *BatchTestSuite) check(cache batchCache) {
+	s.Equal(0, cache.AddMany(batchEntries(1, 2, 3, 4, 5)))
+	s.Equal(2, cache.AddMany(batchEntries(2, 4, 6)))
+	s.Equal(6, cache.Len())
+
+	found, missing := cache.GetMany([]int{6, 9, 1, 3, 8})
+	s.Equal(batchEntries(6, 1, 3), found)
+	s.Equal([]int{9, 8}, missing)
+
+	s.Equal(3, cache.RemoveMany([]int{1, 2, 7, 3}))
+	found, missing = cache.GetMany([]int{1, 4})
+	s.Equal(batchEntries(4), found)
+	s.Equal([]int{1}, missing)
+}
+
+func (s *BatchTestSuite) TestLru() {
+	lru := NewLruWithCapacity[int, string](4, nil)
+	lru.AddMany(batchEntries(1, 2, 3, 4))
+	found, _ := lru.GetMany([]int{1})
+	s.Equal(batchEntries(1), found)
+	lru.AddMany(batchEntries(5))
+	s.False(lru.Contains(2))
+
+	s.check(NewLru[int, string]())
+}
+
+func (s *BatchTestSuite) TestSyncLru() {
+	s.check(NewSyncLru[int, string](0, nil))
+}
+
+func (s *BatchTestSuite) TestShardedLru() {
+	s.check(NewShardedLru[int, string](4, 0, nil, nil))
+}
+
+func (s *BatchTestSuite) TestBatchLoader() {
+	var batches [][]int
+	fail := false
+	cache := NewLoadingCache(16, func(ctx context.Context, key int) (string, error) {
+		if key == 13 {
+			return "", errors.New("unlucky")
+		}
+		return fmt.Sprintf("value-%v", key), nil
+	})
+
+	// without a batch loader keys are loaded one by one
+	found, missing, err := cache.GetOrLoadMany(context.Background(), []int{1, 13, 2})
+	s.EqualError(err, "unlucky")
+	s.Equal(batchEntries(1, 2), found)
+	s.Equal([]int{13}, missing)
+
+	cache.SetBatchLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		batches = append(batches, keys)
+		if fail {
+			return nil, errors.New("backend down")
+		}
+		values := make(map[int]string)
+		for _, key := range keys {
+			if key%2 == 1 {
+				values[key] = fmt.Sprintf("value-%v", key)
+			}
+		}
+		return values, nil
+	})
+	found, missing, err = cache.GetOrLoadMany(context.Background(), []int{3, 1, 4, 5, 2})
+	s.Nil(err)
+	s.Equal(batchEntries(3, 1, 5, 2), found)
+	s.Equal([]int{4}, missing)
+	s.Equal([][]int{{3, 4, 5}}, batches)
+	s.Equal(4, cache.Len())
+
+	fail = true
+	found, missing, err = cache.GetOrLoadMany(context.Background(), []int{5, 6})
+	s.EqualError(err, "backend down")
+	s.Equal(batchEntries(5), found)
+	s.Equal([]int{6}, missing)
+	s.Equal([]int{6}, batches[1])
+}
+
+func (s *BatchTestSuite) TestBatchLoaderErrors() {
+	var batches [][]int
+	cache := NewLoadingCache(16, func(ctx context.Context, key int) (string, error) {
+		return "", errors.New("unlucky")
+	})
+	cache.SetNegativeTTL(time.Minute)
+	cache.SetBatchLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		batches = append(batches, keys)
+		// a concurrent single load fails meanwhile
+		cache.GetOrLoad(ctx, 1)
+		return map[int]string{1: "value-1"}, nil
+	})
+
+	found, missing, err := cache.GetOrLoadMany(context.Background(), []int{1, 1})
+	s.Nil(err)
+	s.Equal(batchEntries(1, 1), found)
+	s.Nil(missing)
+	s.Equal([][]int{{1}}, batches)
+	// the batch load succeeded after the failure, its error is forgotten
+	s.False(cache.errors.Contains(1))
+}
+
+func TestBatchTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchTestSuite))
+}
+
+const batchBenchmarkSize = 100
+
+func BenchmarkSyncLruGetEach(b *testing.B) {
+	cache := NewSyncLru[int, int](benchmarkKeys, nil)
+	keys := make([]int, batchBenchmarkSize)
+	for i := range keys {
+		keys[i] = i
+		cache.Add(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, key := range keys {
+			cache.Get(key)
+		}
+	}
+}
+
+func BenchmarkSyncLruGetMany(b *testing.B) {
+	cache := NewSyncLru[int, int](benchmarkKeys, nil)
+	keys := make([]int, batchBenchmarkSize)
+	for i := range keys {
+		keys[i] = i
+		cache.Add(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.GetMany(keys)
+	}
+}
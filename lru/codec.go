@@ -0,0 +1,50 @@
+package lru
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// Encoder writes a stream of values, e.g. *gob.Encoder or *json.Encoder
+type Encoder interface {
+	Encode(value any) error
+}
+
+// Decoder reads back a stream written by the matching Encoder
+type Decoder interface {
+	Decode(value any) error
+}
+
+// Codec serializes snapshots
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+func (gobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (jsonCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+var (
+	// GobCodec is compact and fast, interface values need gob.Register
+	GobCodec Codec = gobCodec{}
+	// JSONCodec is readable, keys and values must round trip through json
+	JSONCodec Codec = jsonCodec{}
+)
@@ -0,0 +1,81 @@
+package lru_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/yixiaoyang/simpelib/lru"
+	"github.com/yixiaoyang/simpelib/lru/lrutest"
+)
+
+func TestArcConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewArc[int, string](capacity, nil)
+	}})
+}
+
+func TestArenaLruConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewArenaLru[int, string](capacity, nil)
+	}})
+}
+
+func TestCostLruConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewCostLru[int, string](int64(capacity), nil, nil)
+	}})
+}
+
+func TestLfuConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewLfu[int, string](capacity, nil)
+	}})
+}
+
+func TestLruConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewLruWithCapacity[int, string](capacity, nil)
+	}})
+}
+
+func TestLruKConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewLruK[int, string](capacity, 2, nil)
+	}})
+}
+
+func TestShardedLruConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewShardedLru[int, string](4, capacity, nil, nil)
+	}})
+}
+
+func TestSieveConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewSieve[int, string](capacity, nil)
+	}})
+}
+
+func TestSyncLruConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewSyncLru[int, string](capacity, nil)
+	}})
+}
+
+func TestTinyLfuConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewTinyLfu[int, string](capacity, nil, nil)
+	}})
+}
+
+func TestTwoQueueConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewTwoQueue[int, string](capacity, 0, 0, nil)
+	}})
+}
+
+func TestSlruConformance(t *testing.T) {
+	suite.Run(t, &lrutest.ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+		return lru.NewSlru[int, string](capacity, 0, nil)
+	}})
+}
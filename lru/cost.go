@@ -0,0 +1,172 @@
+package lru
+
+import "errors"
+
+// ErrCostTooLarge is returned when a single entry costs more than the whole
+// capacity of a CostLru
+var ErrCostTooLarge = errors.New("lru: entry cost exceeds cache capacity")
+
+// Sizer computes the cost of an entry, e.g. its size in bytes
+type Sizer[k comparable, v any] func(key k, value v) int64
+
+type costEntry[v any] struct {
+	value v
+	cost  int64
+}
+
+// CostLru is a non-thread-safe lru bounded by the total cost of its entries
+// instead of their count. An entry costing more than the capacity is never
+// stored: Add drops it silently while AddWithCost returns ErrCostTooLarge,
+// in both cases a previous value of the key is removed.
+type CostLru[k comparable, v any] struct {
+	list     *List[k, costEntry[v]]
+	hash     map[k]*Node[k, costEntry[v]]
+	capacity int64
+	cost     int64
+	sizer    Sizer[k, v]
+	onEvict  EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*CostLru[int, int])(nil)
+
+// NewCostLru returns a lru holding entries up to a total cost of capacity,
+// capacity <= 0 means unbounded. Add computes costs with sizer, a nil sizer
+// gives every entry a cost of 1.
+func NewCostLru[k comparable, v any](capacity int64, sizer Sizer[k, v], onEvict EvictFunc[k, v]) *CostLru[k, v] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	if sizer == nil {
+		sizer = func(key k, value v) int64 {
+			return 1
+		}
+	}
+	return &CostLru[k, v]{
+		list:     NewList[k, costEntry[v]](),
+		hash:     make(map[k]*Node[k, costEntry[v]]),
+		capacity: capacity,
+		sizer:    sizer,
+		onEvict:  onEvict,
+	}
+}
+
+func (lru *CostLru[k, v]) Add(key k, value v) (overwrite bool) {
+	overwrite, _ = lru.AddWithCost(key, value, lru.sizer(key, value))
+	return overwrite
+}
+
+// AddWithCost adds an entry with an explicit cost, negative costs count as 0.
+// Entries are evicted from the tail until the total cost fits.
+func (lru *CostLru[k, v]) AddWithCost(key k, value v, cost int64) (overwrite bool, err error) {
+	if cost < 0 {
+		cost = 0
+	}
+	node, ok := lru.hash[key]
+	if lru.capacity > 0 && cost > lru.capacity {
+		if ok {
+			lru.removeNode(node, EvictReasonCapacity)
+		}
+		return false, ErrCostTooLarge
+	}
+	if ok {
+		lru.cost += cost - node.value.cost
+		node.value = costEntry[v]{value: value, cost: cost}
+		lru.list.MoveToFront(node)
+	} else {
+		lru.hash[key] = lru.list.Prepend(key, costEntry[v]{value: value, cost: cost})
+		lru.cost += cost
+	}
+	lru.trim()
+	return ok, nil
+}
+
+func (lru *CostLru[k, v]) trim() (evicted int) {
+	if lru.capacity <= 0 {
+		return 0
+	}
+	for lru.cost > lru.capacity {
+		lru.removeNode(lru.list.Back(), EvictReasonCapacity)
+		evicted++
+	}
+	return evicted
+}
+
+func (lru *CostLru[k, v]) removeNode(node *Node[k, costEntry[v]], reason EvictReason) {
+	lru.list.Remove(node)
+	delete(lru.hash, node.key)
+	lru.cost -= node.value.cost
+	if lru.onEvict != nil {
+		lru.onEvict(node.key, node.value.value, reason)
+	}
+}
+
+func (lru *CostLru[k, v]) Get(key k) (value v, exist bool) {
+	if node, ok := lru.hash[key]; ok {
+		lru.list.MoveToFront(node)
+		return node.value.value, true
+	}
+	return value, false
+}
+
+func (lru *CostLru[k, v]) Peek(key k) (value v, exist bool) {
+	if node, ok := lru.hash[key]; ok {
+		return node.value.value, true
+	}
+	return value, false
+}
+
+func (lru *CostLru[k, v]) Remove(key k) (exist bool) {
+	if node, ok := lru.hash[key]; ok {
+		lru.removeNode(node, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+func (lru *CostLru[k, v]) RemoveOldest() (key k, value v) {
+	node := lru.list.Back()
+	if node == nil {
+		return
+	}
+	lru.removeNode(node, EvictReasonRemoved)
+	return node.key, node.value.value
+}
+
+func (lru *CostLru[k, v]) Clear() {
+	list := lru.list
+	lru.hash = make(map[k]*Node[k, costEntry[v]])
+	lru.list = NewList[k, costEntry[v]]()
+	lru.cost = 0
+	if lru.onEvict != nil {
+		list.Iterate(func(key k, entry costEntry[v]) bool {
+			lru.onEvict(key, entry.value, EvictReasonCleared)
+			return false
+		})
+	}
+}
+
+func (lru *CostLru[k, v]) Len() int {
+	return lru.list.Len()
+}
+
+// Cap returns the cost budget, 0 means unbounded
+func (lru *CostLru[k, v]) Cap() int64 {
+	return lru.capacity
+}
+
+// CurrentCost returns the total cost of cached entries
+func (lru *CostLru[k, v]) CurrentCost() int64 {
+	return lru.cost
+}
+
+func (lru *CostLru[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	lru.list.Iterate(func(key k, entry costEntry[v]) bool {
+		return iterateFunc(key, entry.value)
+	})
+}
+
+func (lru *CostLru[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	lru.list.IterateReverse(func(key k, entry costEntry[v]) bool {
+		return iterateFunc(key, entry.value)
+	})
+}
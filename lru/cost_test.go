@@ -0,0 +1,63 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CostLruTestSuite struct {
+	suite.Suite
+	lru     *CostLru[string, []byte]
+	evicted []string
+}
+
+func (s *CostLruTestSuite) SetupTest() {
+	s.evicted = nil
+	s.lru = NewCostLru(10, func(key string, value []byte) int64 {
+		return int64(len(value))
+	}, func(key string, value []byte, reason EvictReason) {
+		s.evicted = append(s.evicted, key)
+	})
+}
+
+func (s *CostLruTestSuite) TestEvictByCost() {
+	s.lru.Add("a", make([]byte, 4))
+	s.lru.Add("b", make([]byte, 4))
+	s.Equal(int64(8), s.lru.CurrentCost())
+
+	s.lru.Add("c", make([]byte, 7))
+	s.Equal([]string{"a", "b"}, s.evicted)
+	s.Equal(int64(7), s.lru.CurrentCost())
+	s.Equal(1, s.lru.Len())
+
+	s.True(s.lru.Add("c", make([]byte, 2)))
+	s.Equal(int64(2), s.lru.CurrentCost())
+}
+
+func (s *CostLruTestSuite) TestExplicitCost() {
+	overwrite, err := s.lru.AddWithCost("a", nil, 7)
+	s.False(overwrite)
+	s.Nil(err)
+	_, err = s.lru.AddWithCost("b", nil, 3)
+	s.Nil(err)
+	s.Equal(int64(10), s.lru.CurrentCost())
+	s.Equal(2, s.lru.Len())
+}
+
+func (s *CostLruTestSuite) TestTooLarge() {
+	s.lru.Add("a", make([]byte, 4))
+	s.lru.Add("b", make([]byte, 4))
+
+	_, err := s.lru.AddWithCost("a", nil, 11)
+	s.ErrorIs(err, ErrCostTooLarge)
+	s.Equal([]string{"a"}, s.evicted)
+	s.False(s.lru.Add("c", make([]byte, 11)))
+	_, ok := s.lru.Get("c")
+	s.False(ok)
+	s.Equal(int64(4), s.lru.CurrentCost())
+}
+
+func TestCostLruTestSuite(t *testing.T) {
+	suite.Run(t, new(CostLruTestSuite))
+}
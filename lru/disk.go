@@ -0,0 +1,388 @@
+package lru
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrCorruptRecord is returned when a DiskStore record fails its checksum
+var ErrCorruptRecord = errors.New("lru: disk record checksum mismatch")
+
+// ErrRecordTooLarge is returned when a single record exceeds the capacity
+// of a DiskStore
+var ErrRecordTooLarge = errors.New("lru: disk record exceeds store capacity")
+
+// DefaultSegmentSize is the segment size of an unbounded DiskStore
+const DefaultSegmentSize = 64 << 20
+
+const (
+	segmentExt = ".seg"
+	// diskHeaderSize is the size of the record header: crc32 of the rest of
+	// the record, kind, key length and value length
+	diskHeaderSize = 13
+
+	recordValue     byte = 1
+	recordTombstone byte = 2
+)
+
+// DiskStore keeps []byte values in append-only segment files of a
+// directory with an in-memory index of their records. Every record carries
+// a crc32 checked on read. Removals append tombstones, and the oldest
+// segment is dropped as a whole once the files exceed the capacity. On
+// open the index is rebuilt by scanning the segments, a segment is cut at
+// its first torn or corrupt record. Writes are not synced, a crash may
+// lose the latest ones, tombstones included. A corrupt record followed by
+// more data is not a torn write: the records cut after it may hold
+// tombstones, so every record recovered up to it is dropped rather than
+// risking a removed key coming back.
+type DiskStore struct {
+	lock        sync.Mutex
+	dir         string
+	capacity    int64
+	segmentSize int64
+	size        int64
+	// segments are ordered from oldest to active
+	segments []*diskSegment
+	nextID   int
+	index    map[string]diskLocation
+	closed   bool
+}
+
+type diskSegment struct {
+	id   int
+	file *os.File
+	size int64
+}
+
+type diskLocation struct {
+	segment *diskSegment
+	offset  int64
+	size    int64
+}
+
+// OpenDiskStore opens or creates dir and recovers the records of its
+// segments. capacity <= 0 means unbounded, segmentSize <= 0 picks an eighth
+// of capacity, or DefaultSegmentSize if unbounded.
+func OpenDiskStore(dir string, capacity int64, segmentSize int64) (*DiskStore, error) {
+	if capacity < 0 {
+		capacity = 0
+	}
+	if segmentSize <= 0 {
+		segmentSize = DefaultSegmentSize
+		if capacity > 0 {
+			segmentSize = capacity / 8
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	store := &DiskStore{
+		dir:         dir,
+		capacity:    capacity,
+		segmentSize: segmentSize,
+		index:       make(map[string]diskLocation),
+	}
+	if err := store.recover(); err != nil {
+		store.Close()
+		return nil, err
+	}
+	// recovered segments are never appended to
+	if err := store.roll(); err != nil {
+		store.Close()
+		return nil, err
+	}
+	if err := store.trim(); err != nil {
+		store.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (store *DiskStore) recover() error {
+	names, err := filepath.Glob(filepath.Join(store.dir, "*"+segmentExt))
+	if err != nil {
+		return err
+	}
+	var ids []int
+	for _, name := range names {
+		var id int
+		if _, err := fmt.Sscanf(strings.TrimSuffix(filepath.Base(name), segmentExt), "%d", &id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		file, err := os.OpenFile(store.segmentPath(id), os.O_RDWR, 0o644)
+		if err != nil {
+			return err
+		}
+		segment := &diskSegment{id: id, file: file}
+		store.segments = append(store.segments, segment)
+		store.nextID = id + 1
+		if err := store.scan(segment); err != nil {
+			return err
+		}
+		store.size += segment.size
+	}
+	return nil
+}
+
+// scan indexes the records of segment and truncates it after the last
+// valid one
+func (store *DiskStore) scan(segment *diskSegment) error {
+	info, err := segment.file.Stat()
+	if err != nil {
+		return err
+	}
+	reader := io.NewSectionReader(segment.file, 0, info.Size())
+	var offset int64
+	for {
+		key, _, kind, size, err := readRecord(reader, offset, info.Size()-offset)
+		if err != nil {
+			if err == ErrCorruptRecord && offset+size < info.Size() {
+				// the cut records may remove keys of the older ones
+				store.index = make(map[string]diskLocation)
+			}
+			break
+		}
+		if kind == recordTombstone {
+			delete(store.index, key)
+		} else {
+			store.index[key] = diskLocation{segment: segment, offset: offset, size: size}
+		}
+		offset += size
+	}
+	segment.size = offset
+	if offset < info.Size() {
+		return segment.file.Truncate(offset)
+	}
+	return nil
+}
+
+// readRecord reads and checks the record at offset, limit bounds its size
+func readRecord(reader io.ReaderAt, offset int64, limit int64) (key string, value []byte, kind byte, size int64, err error) {
+	var header [diskHeaderSize]byte
+	if _, err = reader.ReadAt(header[:], offset); err != nil {
+		return
+	}
+	kind = header[4]
+	keyLen := int64(binary.LittleEndian.Uint32(header[5:]))
+	valueLen := int64(binary.LittleEndian.Uint32(header[9:]))
+	size = diskHeaderSize + keyLen + valueLen
+	if size > limit || (kind != recordValue && kind != recordTombstone) {
+		err = ErrCorruptRecord
+		return
+	}
+	record := make([]byte, size)
+	if _, err = reader.ReadAt(record, offset); err != nil {
+		return
+	}
+	if crc32.ChecksumIEEE(record[4:]) != binary.LittleEndian.Uint32(record) {
+		err = ErrCorruptRecord
+		return
+	}
+	key = string(record[diskHeaderSize : diskHeaderSize+keyLen])
+	value = record[diskHeaderSize+keyLen:]
+	return
+}
+
+func (store *DiskStore) segmentPath(id int) string {
+	return filepath.Join(store.dir, fmt.Sprintf("%08d%s", id, segmentExt))
+}
+
+// roll opens a new active segment
+func (store *DiskStore) roll() error {
+	id := store.nextID
+	store.nextID++
+	file, err := os.OpenFile(store.segmentPath(id), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	store.segments = append(store.segments, &diskSegment{id: id, file: file})
+	return nil
+}
+
+// trim drops the oldest segments until the files fit the capacity, the
+// active segment is kept
+func (store *DiskStore) trim() error {
+	for store.capacity > 0 && store.size > store.capacity && len(store.segments) > 1 {
+		segment := store.segments[0]
+		store.segments = store.segments[1:]
+		for key, location := range store.index {
+			if location.segment == segment {
+				delete(store.index, key)
+			}
+		}
+		store.size -= segment.size
+		segment.file.Close()
+		if err := os.Remove(segment.file.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *DiskStore) append(kind byte, key string, value []byte) (diskLocation, error) {
+	if store.closed || len(store.segments) == 0 {
+		return diskLocation{}, os.ErrClosed
+	}
+	size := int64(diskHeaderSize + len(key) + len(value))
+	if store.capacity > 0 && size > store.capacity {
+		return diskLocation{}, ErrRecordTooLarge
+	}
+	active := store.segments[len(store.segments)-1]
+	if active.size > 0 && active.size+size > store.segmentSize {
+		if err := store.roll(); err != nil {
+			return diskLocation{}, err
+		}
+		active = store.segments[len(store.segments)-1]
+	}
+	record := make([]byte, size)
+	record[4] = kind
+	binary.LittleEndian.PutUint32(record[5:], uint32(len(key)))
+	binary.LittleEndian.PutUint32(record[9:], uint32(len(value)))
+	copy(record[diskHeaderSize+copy(record[diskHeaderSize:], key):], value)
+	binary.LittleEndian.PutUint32(record, crc32.ChecksumIEEE(record[4:]))
+	if _, err := active.file.WriteAt(record, active.size); err != nil {
+		return diskLocation{}, err
+	}
+	location := diskLocation{segment: active, offset: active.size, size: size}
+	active.size += size
+	store.size += size
+	return location, nil
+}
+
+// Put stores value, ErrRecordTooLarge is returned if the record exceeds the
+// capacity and a previous value of key is removed
+func (store *DiskStore) Put(key string, value []byte) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if store.closed {
+		return os.ErrClosed
+	}
+	location, err := store.append(recordValue, key, value)
+	if err != nil {
+		store.remove(key)
+		return err
+	}
+	store.index[key] = location
+	return store.trim()
+}
+
+// Get reads the value of key, a record failing its checksum is dropped and
+// reported with ErrCorruptRecord
+func (store *DiskStore) Get(key string) (value []byte, exist bool, err error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if store.closed {
+		return nil, false, os.ErrClosed
+	}
+	location, ok := store.index[key]
+	if !ok {
+		return nil, false, nil
+	}
+	_, value, _, _, err = readRecord(location.segment.file, location.offset, location.size)
+	if err != nil {
+		store.remove(key)
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Remove appends a tombstone so key stays removed after a restart
+func (store *DiskStore) Remove(key string) (exist bool, err error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if store.closed {
+		return false, os.ErrClosed
+	}
+	if _, ok := store.index[key]; !ok {
+		return false, nil
+	}
+	return true, store.remove(key)
+}
+
+func (store *DiskStore) remove(key string) error {
+	if _, ok := store.index[key]; !ok {
+		return nil
+	}
+	delete(store.index, key)
+	if _, err := store.append(recordTombstone, key, nil); err != nil {
+		return err
+	}
+	return store.trim()
+}
+
+func (store *DiskStore) Contains(key string) bool {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	_, ok := store.index[key]
+	return ok
+}
+
+// Len counts the live records
+func (store *DiskStore) Len() int {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return len(store.index)
+}
+
+// Size returns the bytes taken by the segment files, including removed and
+// replaced records not dropped yet
+func (store *DiskStore) Size() int64 {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return store.size
+}
+
+// Clear deletes every segment and starts over with an empty one, the store
+// stays usable even if a segment file could not be removed
+func (store *DiskStore) Clear() error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if store.closed {
+		return os.ErrClosed
+	}
+	segments := store.segments
+	store.segments = nil
+	store.index = make(map[string]diskLocation)
+	store.size = 0
+	var err error
+	for _, segment := range segments {
+		segment.file.Close()
+		if removeErr := os.Remove(segment.file.Name()); removeErr != nil && err == nil {
+			err = removeErr
+		}
+	}
+	if rollErr := store.roll(); rollErr != nil {
+		store.closed = true
+		return rollErr
+	}
+	return err
+}
+
+// Close closes the segment files, the store is unusable afterwards: it
+// reports no entry and its methods returning an error return os.ErrClosed
+func (store *DiskStore) Close() error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.closed = true
+	store.index = make(map[string]diskLocation)
+	store.size = 0
+	var err error
+	for _, segment := range store.segments {
+		if closeErr := segment.file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	store.segments = nil
+	return err
+}
@@ -0,0 +1,161 @@
+package lru
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DiskStoreTestSuite struct {
+	suite.Suite
+	dir   string
+	store *DiskStore
+}
+
+func (s *DiskStoreTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.store = s.open(0, 0)
+}
+
+func (s *DiskStoreTestSuite) TearDownTest() {
+	s.store.Close()
+}
+
+func (s *DiskStoreTestSuite) open(capacity int64, segmentSize int64) *DiskStore {
+	store, err := OpenDiskStore(s.dir, capacity, segmentSize)
+	s.Require().Nil(err)
+	return store
+}
+
+func (s *DiskStoreTestSuite) reopen() {
+	s.Nil(s.store.Close())
+	s.store = s.open(0, 0)
+}
+
+func (s *DiskStoreTestSuite) TestPutGet() {
+	s.Nil(s.store.Put("a", []byte("one")))
+	s.Nil(s.store.Put("b", []byte("two")))
+	s.Nil(s.store.Put("a", []byte("three")))
+	value, ok, err := s.store.Get("a")
+	s.Nil(err)
+	s.True(ok)
+	s.Equal([]byte("three"), value)
+
+	ok, err = s.store.Remove("b")
+	s.True(ok)
+	s.Nil(err)
+	_, ok, err = s.store.Get("b")
+	s.False(ok)
+	s.Nil(err)
+	s.Equal(1, s.store.Len())
+	s.Equal(int64(4*diskHeaderSize+4+11), s.store.Size())
+}
+
+func (s *DiskStoreTestSuite) TestRecovery() {
+	s.store.Put("a", []byte("one"))
+	s.store.Put("b", []byte("two"))
+	s.store.Put("a", []byte("three"))
+	s.store.Remove("b")
+	s.reopen()
+
+	s.Equal(1, s.store.Len())
+	value, ok, _ := s.store.Get("a")
+	s.True(ok)
+	s.Equal([]byte("three"), value)
+	s.False(s.store.Contains("b"))
+
+	// writes after a restart go to a new segment
+	s.store.Put("c", []byte("four"))
+	s.reopen()
+	s.Equal(2, s.store.Len())
+	names, _ := filepath.Glob(filepath.Join(s.dir, "*"+segmentExt))
+	s.Equal(3, len(names))
+}
+
+func (s *DiskStoreTestSuite) TestCorruption() {
+	s.store.Put("a", []byte("one"))
+	s.store.Put("b", []byte("two"))
+	path := s.store.segments[len(s.store.segments)-1].file.Name()
+	s.store.Close()
+
+	// a torn record cuts the segment on recovery
+	info, _ := os.Stat(path)
+	s.Nil(os.Truncate(path, info.Size()-1))
+	s.store = s.open(0, 0)
+	s.False(s.store.Contains("b"))
+	info, _ = os.Stat(path)
+	s.Equal(int64(diskHeaderSize+4), info.Size())
+
+	// flip a value byte of the remaining record
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	s.Require().Nil(err)
+	file.WriteAt([]byte{'x'}, diskHeaderSize+1)
+	file.Close()
+	_, ok, err := s.store.Get("a")
+	s.False(ok)
+	s.ErrorIs(err, ErrCorruptRecord)
+	s.False(s.store.Contains("a"))
+	s.reopen()
+	s.Equal(0, s.store.Len())
+}
+
+func (s *DiskStoreTestSuite) TestCorruptionKeepsRemoved() {
+	s.store.Put("a", []byte("one"))
+	s.reopen()
+	s.store.Put("b", []byte("two"))
+	s.store.Remove("a")
+	path := s.store.segments[len(s.store.segments)-1].file.Name()
+	s.store.Close()
+
+	// the tombstone of a sits after the corrupt record of b
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	s.Require().Nil(err)
+	file.WriteAt([]byte{'x'}, diskHeaderSize+1)
+	file.Close()
+	s.store = s.open(0, 0)
+	s.False(s.store.Contains("a"))
+	s.False(s.store.Contains("b"))
+}
+
+func (s *DiskStoreTestSuite) TestCapacity() {
+	s.store.Close()
+	recordSize := int64(diskHeaderSize + 6 + 8)
+	s.store = s.open(recordSize*8, recordSize*2)
+	for i := 0; i < 20; i++ {
+		s.Nil(s.store.Put(fmt.Sprintf("key-%02d", i), []byte(fmt.Sprintf("value-%02d", i))))
+		s.LessOrEqual(s.store.Size(), recordSize*8)
+	}
+	// whole segments of the oldest records are dropped
+	s.Equal(8, s.store.Len())
+	s.False(s.store.Contains("key-11"))
+	s.True(s.store.Contains("key-12"))
+	s.ErrorIs(s.store.Put("big", make([]byte, recordSize*8)), ErrRecordTooLarge)
+
+	s.Nil(s.store.Clear())
+	s.Equal(0, s.store.Len())
+	s.Equal(int64(0), s.store.Size())
+	s.reopen()
+	s.Equal(0, s.store.Len())
+}
+
+func (s *DiskStoreTestSuite) TestClosed() {
+	s.Nil(s.store.Put("a", []byte("1")))
+	s.Nil(s.store.Close())
+	s.False(s.store.Contains("a"))
+	s.Equal(0, s.store.Len())
+	s.ErrorIs(s.store.Put("b", []byte("2")), os.ErrClosed)
+	exist, err := s.store.Remove("a")
+	s.False(exist)
+	s.ErrorIs(err, os.ErrClosed)
+	_, _, err = s.store.Get("a")
+	s.ErrorIs(err, os.ErrClosed)
+	s.ErrorIs(s.store.Clear(), os.ErrClosed)
+	s.Nil(s.store.Close())
+}
+
+func TestDiskStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(DiskStoreTestSuite))
+}
@@ -0,0 +1,173 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// EventKind tells what happened to a cache entry
+type EventKind int
+
+const (
+	// EventAdded: a new key was inserted
+	EventAdded EventKind = iota
+	// EventUpdated: the value of a cached key was replaced
+	EventUpdated
+	// EventEvicted: the entry left the cache, Event.Reason tells why
+	EventEvicted
+	// EventExpired: the entry outlived its ttl and was dropped
+	EventExpired
+)
+
+func (kind EventKind) String() string {
+	switch kind {
+	case EventAdded:
+		return "added"
+	case EventUpdated:
+		return "updated"
+	case EventEvicted:
+		return "evicted"
+	case EventExpired:
+		return "expired"
+	}
+	return "unknown"
+}
+
+// Event describes a cache mutation, Reason is only set for EventEvicted and
+// EventExpired
+type Event[k comparable, v any] struct {
+	Kind   EventKind
+	Key    k
+	Value  v
+	Reason EvictReason
+}
+
+// OverflowPolicy tells what a cache does when a subscriber buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowDrop: the event is dropped for this subscriber and counted
+	OverflowDrop OverflowPolicy = iota
+	// OverflowBlock: the cache waits until the subscriber reads or
+	// unsubscribes, every cache operation stalls meanwhile
+	OverflowBlock
+	// OverflowDisconnect: the subscriber is unsubscribed and its channel
+	// closed
+	OverflowDisconnect
+)
+
+// Subscription receives the events of a cache until Unsubscribe, its
+// channel is closed once it is unsubscribed
+type Subscription[k comparable, v any] struct {
+	events  chan Event[k, v]
+	policy  OverflowPolicy
+	dropped uint64
+	// done is closed first by Unsubscribe to release a blocked publisher
+	done chan struct{}
+	once sync.Once
+}
+
+// Events returns the channel delivering the events in cache order
+func (sub *Subscription[k, v]) Events() <-chan Event[k, v] {
+	return sub.events
+}
+
+// Dropped counts events dropped by OverflowDrop
+func (sub *Subscription[k, v]) Dropped() uint64 {
+	return atomic.LoadUint64(&sub.dropped)
+}
+
+func (sub *Subscription[k, v]) cancel() {
+	sub.once.Do(func() {
+		close(sub.done)
+	})
+}
+
+// deliver sends event according to the policy, it returns false if the
+// subscriber must be disconnected
+func (sub *Subscription[k, v]) deliver(event Event[k, v]) bool {
+	select {
+	case sub.events <- event:
+		return true
+	case <-sub.done:
+		return true
+	default:
+	}
+	switch sub.policy {
+	case OverflowBlock:
+		select {
+		case sub.events <- event:
+		case <-sub.done:
+		}
+	case OverflowDisconnect:
+		return false
+	default:
+		atomic.AddUint64(&sub.dropped, 1)
+	}
+	return true
+}
+
+// Subscribe registers a subscriber with a buffer of buffer events, the
+// events are sent synchronously by the cache operations causing them
+func (lru *Lru[k, v]) Subscribe(buffer int, policy OverflowPolicy) *Subscription[k, v] {
+	if buffer < 0 {
+		buffer = 0
+	}
+	sub := &Subscription[k, v]{
+		events: make(chan Event[k, v], buffer),
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+	lru.subscribers = append(lru.subscribers, sub)
+	return sub
+}
+
+// Unsubscribe stops the events of sub and closes its channel, it returns
+// false if sub was already unsubscribed
+func (lru *Lru[k, v]) Unsubscribe(sub *Subscription[k, v]) bool {
+	sub.cancel()
+	for i, subscriber := range lru.subscribers {
+		if subscriber == sub {
+			lru.subscribers = append(lru.subscribers[:i], lru.subscribers[i+1:]...)
+			close(sub.events)
+			return true
+		}
+	}
+	return false
+}
+
+func (lru *Lru[k, v]) publish(kind EventKind, key k, value v, reason EvictReason) {
+	if len(lru.subscribers) == 0 {
+		return
+	}
+	event := Event[k, v]{Kind: kind, Key: key, Value: value, Reason: reason}
+	for i := 0; i < len(lru.subscribers); i++ {
+		if !lru.subscribers[i].deliver(event) {
+			lru.Unsubscribe(lru.subscribers[i])
+			i--
+		}
+	}
+}
+
+// publishEvict reports an entry removed for reason
+func (lru *Lru[k, v]) publishEvict(key k, value v, reason EvictReason) {
+	if reason == EvictReasonExpired {
+		lru.publish(EventExpired, key, value, reason)
+	} else {
+		lru.publish(EventEvicted, key, value, reason)
+	}
+}
+
+func (cache *SyncLru[k, v]) Subscribe(buffer int, policy OverflowPolicy) *Subscription[k, v] {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Subscribe(buffer, policy)
+}
+
+// Unsubscribe may be called while the cache is blocked on sub
+func (cache *SyncLru[k, v]) Unsubscribe(sub *Subscription[k, v]) bool {
+	sub.cancel()
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Unsubscribe(sub)
+}
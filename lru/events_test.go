@@ -0,0 +1,115 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EventsTestSuite struct {
+	suite.Suite
+	lru *Lru[int, string]
+}
+
+func (s *EventsTestSuite) SetupTest() {
+	s.lru = NewLruWithCapacity[int, string](2, nil)
+}
+
+func drain[k comparable, v any](sub *Subscription[k, v]) []Event[k, v] {
+	var events []Event[k, v]
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func (s *EventsTestSuite) TestEvents() {
+	clock := newFakeClock()
+	s.lru.SetClock(clock)
+	sub := s.lru.Subscribe(16, OverflowDrop)
+
+	s.lru.Add(1, "one")
+	s.lru.Add(1, "uno")
+	s.lru.AddWithTTL(2, "two", time.Second)
+	s.lru.Add(3, "three")
+	s.lru.Add(4, "four")
+	s.lru.Remove(4)
+	s.lru.Add(5, "five")
+	clock.Advance(time.Second)
+	s.lru.Clear()
+	s.Equal([]Event[int, string]{
+		{Kind: EventAdded, Key: 1, Value: "one"},
+		{Kind: EventUpdated, Key: 1, Value: "uno"},
+		{Kind: EventAdded, Key: 2, Value: "two"},
+		{Kind: EventAdded, Key: 3, Value: "three"},
+		{Kind: EventEvicted, Key: 1, Value: "uno", Reason: EvictReasonCapacity},
+		{Kind: EventAdded, Key: 4, Value: "four"},
+		{Kind: EventEvicted, Key: 2, Value: "two", Reason: EvictReasonCapacity},
+		{Kind: EventEvicted, Key: 4, Value: "four", Reason: EvictReasonRemoved},
+		{Kind: EventAdded, Key: 5, Value: "five"},
+		{Kind: EventEvicted, Key: 5, Value: "five", Reason: EvictReasonCleared},
+		{Kind: EventEvicted, Key: 3, Value: "three", Reason: EvictReasonCleared},
+	}, drain(sub))
+
+	s.lru.AddWithTTL(6, "six", time.Second)
+	clock.Advance(time.Second)
+	s.lru.Get(6)
+	s.Equal([]Event[int, string]{
+		{Kind: EventAdded, Key: 6, Value: "six"},
+		{Kind: EventExpired, Key: 6, Value: "six", Reason: EvictReasonExpired},
+	}, drain(sub))
+
+	s.True(s.lru.Unsubscribe(sub))
+	s.False(s.lru.Unsubscribe(sub))
+	_, ok := <-sub.Events()
+	s.False(ok)
+}
+
+func (s *EventsTestSuite) TestDrop() {
+	sub := s.lru.Subscribe(1, OverflowDrop)
+	s.lru.Add(1, "one")
+	s.lru.Add(2, "two")
+	s.Equal(uint64(1), sub.Dropped())
+	s.Equal(1, len(drain(sub)))
+}
+
+func (s *EventsTestSuite) TestDisconnect() {
+	slow := s.lru.Subscribe(1, OverflowDisconnect)
+	fast := s.lru.Subscribe(4, OverflowDisconnect)
+	s.lru.Add(1, "one")
+	s.lru.Add(2, "two")
+	s.Equal(1, len(drain(slow)))
+	s.Equal(2, len(drain(fast)))
+	s.Equal([]*Subscription[int, string]{fast}, s.lru.subscribers)
+	s.False(s.lru.Unsubscribe(slow))
+}
+
+func (s *EventsTestSuite) TestBlock() {
+	cache := NewSyncLru[int, string](2, nil)
+	sub := cache.Subscribe(0, OverflowBlock)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Add(1, "one")
+		cache.Add(2, "two")
+	}()
+	event := <-sub.Events()
+	s.Equal(1, event.Key)
+
+	// unsubscribing releases the blocked Add
+	s.True(cache.Unsubscribe(sub))
+	<-done
+	s.Equal(2, cache.Len())
+}
+
+func TestEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
@@ -0,0 +1,142 @@
+package lru
+
+// Cursor walks the entries of a Lru without updating their recency and
+// skips expired ones. Delete is the only change allowed to the Lru while a
+// cursor is in use.
+type Cursor[k comparable, v any] struct {
+	lru     *Lru[k, v]
+	reverse bool
+	node    *Node[k, v]
+	// nxt is the node to visit after node, read before node may be deleted
+	nxt *Node[k, v]
+	now int64
+}
+
+// Cursor returns a cursor before the most recently used entry, or before
+// the least recently used one if fromOldest
+func (lru *Lru[k, v]) Cursor(fromOldest bool) *Cursor[k, v] {
+	cursor := &Cursor[k, v]{
+		lru:     lru,
+		reverse: fromOldest,
+		now:     lru.clock.Now().UnixNano(),
+	}
+	if fromOldest {
+		cursor.nxt = lru.list.tail.pre
+	} else {
+		cursor.nxt = lru.list.head.nxt
+	}
+	return cursor
+}
+
+// Next moves to the next entry and returns false once there is none
+func (cursor *Cursor[k, v]) Next() bool {
+	list := cursor.lru.list
+	for node := cursor.nxt; node != list.head && node != list.tail; node = cursor.nxt {
+		if cursor.reverse {
+			cursor.nxt = node.pre
+		} else {
+			cursor.nxt = node.nxt
+		}
+		if node.expire == 0 || cursor.now < node.expire {
+			cursor.node = node
+			return true
+		}
+	}
+	cursor.node = nil
+	return false
+}
+
+// Key returns the key of the current entry
+func (cursor *Cursor[k, v]) Key() k {
+	return cursor.node.key
+}
+
+// Value returns the value of the current entry
+func (cursor *Cursor[k, v]) Value() v {
+	return cursor.node.value
+}
+
+// Delete removes the current entry with EvictReasonRemoved, it returns
+// false if the entry is already deleted
+func (cursor *Cursor[k, v]) Delete() bool {
+	node := cursor.node
+	if node == nil || cursor.lru.hash[node.key] != node {
+		return false
+	}
+	cursor.lru.removeNode(node, EvictReasonRemoved)
+	return true
+}
+
+// Keys returns the keys from most to least recently used
+func (lru *Lru[k, v]) Keys() []k {
+	keys := make([]k, 0, lru.Len())
+	lru.Iterate(func(key k, value v) bool {
+		keys = append(keys, key)
+		return false
+	})
+	return keys
+}
+
+// Values returns the values from most to least recently used
+func (lru *Lru[k, v]) Values() []v {
+	values := make([]v, 0, lru.Len())
+	lru.Iterate(func(key k, value v) bool {
+		values = append(values, value)
+		return false
+	})
+	return values
+}
+
+// Entries returns the entries from most to least recently used
+func (lru *Lru[k, v]) Entries() []Entry[k, v] {
+	entries := make([]Entry[k, v], 0, lru.Len())
+	lru.Iterate(func(key k, value v) bool {
+		entries = append(entries, Entry[k, v]{key, value})
+		return false
+	})
+	return entries
+}
+
+func (cache *SyncLru[k, v]) Keys() []k {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Keys()
+}
+
+func (cache *SyncLru[k, v]) Values() []v {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Values()
+}
+
+func (cache *SyncLru[k, v]) Entries() []Entry[k, v] {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Entries()
+}
+
+// Keys returns the keys shard after shard, each from most to least
+// recently used
+func (cache *ShardedLru[k, v]) Keys() []k {
+	var keys []k
+	for _, shard := range cache.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+func (cache *ShardedLru[k, v]) Values() []v {
+	var values []v
+	for _, shard := range cache.shards {
+		values = append(values, shard.Values()...)
+	}
+	return values
+}
+
+func (cache *ShardedLru[k, v]) Entries() []Entry[k, v] {
+	var entries []Entry[k, v]
+	for _, shard := range cache.shards {
+		entries = append(entries, shard.Entries()...)
+	}
+	return entries
+}
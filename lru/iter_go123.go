@@ -0,0 +1,45 @@
+//go:build go1.23
+
+package lru
+
+import "iter"
+
+// All yields the entries from most to least recently used, the loop body
+// may remove the yielded key but must not change other entries
+func (lru *Lru[k, v]) All() iter.Seq2[k, v] {
+	return func(yield func(k, v) bool) {
+		lru.Iterate(func(key k, value v) bool {
+			return !yield(key, value)
+		})
+	}
+}
+
+// Backward yields the entries from least to most recently used, i.e. in
+// eviction order
+func (lru *Lru[k, v]) Backward() iter.Seq2[k, v] {
+	return func(yield func(k, v) bool) {
+		lru.IterateList(func(key k, value v) bool {
+			return !yield(key, value)
+		})
+	}
+}
+
+// All yields a snapshot taken by Entries, so the loop body may use the
+// cache freely
+func (cache *SyncLru[k, v]) All() iter.Seq2[k, v] {
+	return entriesSeq(cache.Entries)
+}
+
+func (cache *ShardedLru[k, v]) All() iter.Seq2[k, v] {
+	return entriesSeq(cache.Entries)
+}
+
+func entriesSeq[k comparable, v any](entries func() []Entry[k, v]) iter.Seq2[k, v] {
+	return func(yield func(k, v) bool) {
+		for _, entry := range entries() {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
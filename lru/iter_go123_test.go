@@ -0,0 +1,60 @@
+//go:build go1.23
+
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SeqTestSuite struct {
+	suite.Suite
+}
+
+func (s *SeqTestSuite) TestLru() {
+	lru := NewLru[int, int]()
+	for i := 0; i < 4; i++ {
+		lru.Add(i, i*10)
+	}
+	var keys []int
+	for key, value := range lru.All() {
+		s.Equal(key*10, value)
+		keys = append(keys, key)
+		if key == 1 {
+			break
+		}
+	}
+	s.Equal([]int{3, 2, 1}, keys)
+
+	keys = keys[:0]
+	for key := range lru.Backward() {
+		keys = append(keys, key)
+		lru.Remove(key)
+	}
+	s.Equal([]int{0, 1, 2, 3}, keys)
+	s.Equal(0, lru.Len())
+}
+
+func (s *SeqTestSuite) TestSyncLru() {
+	cache := NewSyncLru[int, int](0, nil)
+	sharded := NewShardedLru[int, int](2, 0, nil, nil)
+	for i := 0; i < 4; i++ {
+		cache.Add(i, i)
+		sharded.Add(i, i)
+	}
+	// the snapshot lets the loop body use the cache
+	for key := range cache.All() {
+		cache.Remove(key)
+	}
+	s.Equal(0, cache.Len())
+	count := 0
+	for range sharded.All() {
+		count++
+	}
+	s.Equal(4, count)
+}
+
+func TestSeqTestSuite(t *testing.T) {
+	suite.Run(t, new(SeqTestSuite))
+}
@@ -0,0 +1,97 @@
+package lru
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IterTestSuite struct {
+	suite.Suite
+	lru *Lru[int, string]
+}
+
+func (s *IterTestSuite) SetupTest() {
+	s.lru = NewLru[int, string]()
+	for i := 0; i < 6; i++ {
+		s.lru.Add(i, fmt.Sprintf("value-%v", i))
+	}
+}
+
+func (s *IterTestSuite) TestCursor() {
+	var keys []int
+	for cursor := s.lru.Cursor(false); cursor.Next(); {
+		keys = append(keys, cursor.Key())
+		s.Equal(fmt.Sprintf("value-%v", cursor.Key()), cursor.Value())
+	}
+	s.Equal([]int{5, 4, 3, 2, 1, 0}, keys)
+
+	keys = keys[:0]
+	for cursor := s.lru.Cursor(true); cursor.Next(); {
+		keys = append(keys, cursor.Key())
+	}
+	s.Equal([]int{0, 1, 2, 3, 4, 5}, keys)
+	s.False(s.lru.Cursor(false).Delete())
+}
+
+func (s *IterTestSuite) TestCursorDelete() {
+	clock := newFakeClock()
+	s.lru.SetClock(clock)
+	s.lru.AddWithTTL(6, "value-6", time.Second)
+	clock.Advance(time.Second)
+
+	cursor := s.lru.Cursor(true)
+	for cursor.Next() {
+		if cursor.Key()%2 == 0 {
+			s.True(cursor.Delete())
+			s.False(cursor.Delete())
+		}
+	}
+	s.False(cursor.Next())
+	s.Equal([]int{5, 3, 1}, s.lru.Keys())
+	// the expired entry is skipped, not deleted
+	s.Equal(4, s.lru.Len())
+	s.Equal(uint64(3), s.lru.Stats().Evictions[EvictReasonRemoved])
+}
+
+func (s *IterTestSuite) TestRemoveWhileIterating() {
+	s.lru.Iterate(func(key int, value string) bool {
+		if key%2 == 1 {
+			s.lru.Remove(key)
+		}
+		return false
+	})
+	s.Equal([]int{4, 2, 0}, s.lru.Keys())
+	s.lru.IterateList(func(key int, value string) bool {
+		s.lru.Remove(key)
+		return false
+	})
+	s.Equal(0, s.lru.Len())
+}
+
+func (s *IterTestSuite) TestSnapshots() {
+	s.lru.Get(2)
+	s.Equal([]int{2, 5, 4, 3, 1, 0}, s.lru.Keys())
+	s.Equal("value-2", s.lru.Values()[0])
+	s.Equal(Entry[int, string]{0, "value-0"}, s.lru.Entries()[5])
+
+	cache := NewSyncLru[int, string](0, nil)
+	cache.Add(1, "one")
+	cache.Add(2, "two")
+	s.Equal([]int{2, 1}, cache.Keys())
+	s.Equal([]string{"two", "one"}, cache.Values())
+
+	sharded := NewShardedLru[int, string](4, 0, nil, nil)
+	for i := 0; i < 10; i++ {
+		sharded.Add(i, fmt.Sprintf("value-%v", i))
+	}
+	s.ElementsMatch([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, sharded.Keys())
+	s.Equal(10, len(sharded.Values()))
+	s.Equal(10, len(sharded.Entries()))
+}
+
+func TestIterTestSuite(t *testing.T) {
+	suite.Run(t, new(IterTestSuite))
+}
@@ -0,0 +1,216 @@
+package lru
+
+// lfuBucket groups the entries sharing an access count, buckets are linked
+// by increasing frequency so the lowest one is always at hand
+type lfuBucket[k comparable, v any] struct {
+	freq     int
+	entries  *List[k, lfuEntry[k, v]]
+	pre, nxt *lfuBucket[k, v]
+}
+
+type lfuEntry[k comparable, v any] struct {
+	value  v
+	bucket *lfuBucket[k, v]
+}
+
+// Lfu implements a non-thread-safe O(1) lfu cache: entries sharing the same
+// access count live in one List bucket, buckets are linked by frequency, the
+// least frequently used entry is evicted and ties are broken by recency.
+type Lfu[k comparable, v any] struct {
+	hash map[k]*Node[k, lfuEntry[k, v]]
+	// buckets is the sentinel of the bucket ring, buckets.nxt has the lowest
+	// frequency
+	buckets  *lfuBucket[k, v]
+	capacity int
+	onEvict  EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*Lfu[int, int])(nil)
+
+// NewLfu returns a lfu holding at most capacity entries, capacity <= 0 means
+// unbounded
+func NewLfu[k comparable, v any](capacity int, onEvict EvictFunc[k, v]) *Lfu[k, v] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &Lfu[k, v]{
+		hash:     make(map[k]*Node[k, lfuEntry[k, v]]),
+		buckets:  newLfuBuckets[k, v](),
+		capacity: capacity,
+		onEvict:  onEvict,
+	}
+}
+
+func newLfuBuckets[k comparable, v any]() *lfuBucket[k, v] {
+	sentinel := &lfuBucket[k, v]{}
+	sentinel.pre = sentinel
+	sentinel.nxt = sentinel
+	return sentinel
+}
+
+// Add inserts a new entry with a frequency of 1, or replaces the value of an
+// existing one and counts it as an access
+func (lfu *Lfu[k, v]) Add(key k, value v) (overwrite bool) {
+	if node, ok := lfu.hash[key]; ok {
+		node.value.value = value
+		lfu.touch(node)
+		return true
+	}
+	if lfu.capacity > 0 && len(lfu.hash) >= lfu.capacity {
+		lfu.removeNode(lfu.victim(), EvictReasonCapacity)
+	}
+	bucket := lfu.buckets.nxt
+	if bucket == lfu.buckets || bucket.freq != 1 {
+		bucket = lfu.insertBucket(lfu.buckets, 1)
+	}
+	lfu.hash[key] = bucket.entries.Prepend(key, lfuEntry[k, v]{value: value, bucket: bucket})
+	return false
+}
+
+func (lfu *Lfu[k, v]) Get(key k) (value v, exist bool) {
+	if node, ok := lfu.hash[key]; ok {
+		lfu.touch(node)
+		return node.value.value, true
+	}
+	return value, false
+}
+
+// Peek returns the value of key without counting an access
+func (lfu *Lfu[k, v]) Peek(key k) (value v, exist bool) {
+	if node, ok := lfu.hash[key]; ok {
+		return node.value.value, true
+	}
+	return value, false
+}
+
+// Frequency returns the access count of key, 0 if absent
+func (lfu *Lfu[k, v]) Frequency(key k) int {
+	if node, ok := lfu.hash[key]; ok {
+		return node.value.bucket.freq
+	}
+	return 0
+}
+
+func (lfu *Lfu[k, v]) Remove(key k) (exist bool) {
+	if node, ok := lfu.hash[key]; ok {
+		lfu.removeNode(node, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest pops the entry lfu would evict next
+func (lfu *Lfu[k, v]) RemoveOldest() (key k, value v) {
+	node := lfu.victim()
+	if node == nil {
+		return
+	}
+	lfu.removeNode(node, EvictReasonRemoved)
+	return node.key, node.value.value
+}
+
+func (lfu *Lfu[k, v]) Clear() {
+	buckets := lfu.buckets
+	lfu.hash = make(map[k]*Node[k, lfuEntry[k, v]])
+	lfu.buckets = newLfuBuckets[k, v]()
+	if lfu.onEvict != nil {
+		for bucket := buckets.nxt; bucket != buckets; bucket = bucket.nxt {
+			bucket.entries.Iterate(func(key k, entry lfuEntry[k, v]) bool {
+				lfu.onEvict(key, entry.value, EvictReasonCleared)
+				return false
+			})
+		}
+	}
+}
+
+func (lfu *Lfu[k, v]) Len() int {
+	return len(lfu.hash)
+}
+
+func (lfu *Lfu[k, v]) Cap() int {
+	return lfu.capacity
+}
+
+// Iterate walks from the most to the least frequently used entry, entries
+// with the same frequency from the most recent one
+func (lfu *Lfu[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	lfu.iterate(false, iterateFunc)
+}
+
+// IterateList walks in eviction order
+func (lfu *Lfu[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	lfu.iterate(true, iterateFunc)
+}
+
+func (lfu *Lfu[k, v]) iterate(reverse bool, iterateFunc IterateFunc[k, v]) {
+	bucket := lfu.buckets.pre
+	if reverse {
+		bucket = lfu.buckets.nxt
+	}
+	for bucket != lfu.buckets {
+		next := bucket.pre
+		if reverse {
+			next = bucket.nxt
+		}
+		stop := false
+		bucket.entries.walk(reverse, func(node *Node[k, lfuEntry[k, v]]) bool {
+			stop = iterateFunc(node.key, node.value.value)
+			return stop
+		})
+		if stop {
+			return
+		}
+		bucket = next
+	}
+}
+
+// insertBucket links a new bucket of freq after pre
+func (lfu *Lfu[k, v]) insertBucket(pre *lfuBucket[k, v], freq int) *lfuBucket[k, v] {
+	bucket := &lfuBucket[k, v]{
+		freq:    freq,
+		entries: NewList[k, lfuEntry[k, v]](),
+		pre:     pre,
+		nxt:     pre.nxt,
+	}
+	pre.nxt.pre = bucket
+	pre.nxt = bucket
+	return bucket
+}
+
+// touch moves node to the head of the next frequency bucket
+func (lfu *Lfu[k, v]) touch(node *Node[k, lfuEntry[k, v]]) {
+	bucket := node.value.bucket
+	next := bucket.nxt
+	if next == lfu.buckets || next.freq != bucket.freq+1 {
+		next = lfu.insertBucket(bucket, bucket.freq+1)
+	}
+	lfu.unlink(node)
+	node.value.bucket = next
+	next.entries.pushFront(node)
+}
+
+// unlink detaches node from its bucket and drops the bucket once empty
+func (lfu *Lfu[k, v]) unlink(node *Node[k, lfuEntry[k, v]]) {
+	bucket := node.value.bucket
+	bucket.entries.Remove(node)
+	if bucket.entries.Len() == 0 {
+		bucket.pre.nxt = bucket.nxt
+		bucket.nxt.pre = bucket.pre
+	}
+}
+
+// victim returns the least recently used entry of the lowest frequency
+func (lfu *Lfu[k, v]) victim() *Node[k, lfuEntry[k, v]] {
+	if len(lfu.hash) == 0 {
+		return nil
+	}
+	return lfu.buckets.nxt.entries.Back()
+}
+
+func (lfu *Lfu[k, v]) removeNode(node *Node[k, lfuEntry[k, v]], reason EvictReason) {
+	lfu.unlink(node)
+	delete(lfu.hash, node.key)
+	if lfu.onEvict != nil {
+		lfu.onEvict(node.key, node.value.value, reason)
+	}
+}
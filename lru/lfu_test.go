@@ -0,0 +1,80 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LfuTestSuite struct {
+	suite.Suite
+	lfu *Lfu[int, string]
+}
+
+func (s *LfuTestSuite) SetupTest() {
+	s.lfu = NewLfu[int, string](3, nil)
+}
+
+func (s *LfuTestSuite) TestEvictLeastFrequent() {
+	s.lfu.Add(1, "one")
+	s.lfu.Add(2, "two")
+	s.lfu.Add(3, "three")
+	s.lfu.Get(1)
+	s.lfu.Get(1)
+	s.lfu.Get(3)
+
+	s.lfu.Add(4, "four")
+	_, ok := s.lfu.Peek(2)
+	s.False(ok)
+	s.Equal(3, s.lfu.Frequency(1))
+	s.Equal(2, s.lfu.Frequency(3))
+	s.Equal(1, s.lfu.Frequency(4))
+
+	// 4 is the only entry with frequency 1
+	s.lfu.Add(5, "five")
+	s.False(s.lfu.Remove(4))
+}
+
+func (s *LfuTestSuite) TestTieByRecency() {
+	s.lfu.Add(1, "one")
+	s.lfu.Add(2, "two")
+	s.lfu.Add(3, "three")
+	s.lfu.Get(1)
+	s.lfu.Get(2)
+	s.lfu.Get(3)
+
+	var keys []int
+	s.lfu.IterateList(func(key int, value string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	s.Equal([]int{1, 2, 3}, keys)
+
+	s.lfu.Add(4, "four")
+	s.lfu.Get(4)
+	s.lfu.Get(4)
+	_, ok := s.lfu.Peek(1)
+	s.False(ok)
+
+	keys = keys[:0]
+	s.lfu.Iterate(func(key int, value string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	s.Equal([]int{4, 3, 2}, keys)
+}
+
+func (s *LfuTestSuite) TestRemoveMinBucket() {
+	s.lfu.Add(1, "one")
+	s.lfu.Add(2, "two")
+	s.lfu.Get(2)
+	s.lfu.Remove(1)
+
+	key, _ := s.lfu.RemoveOldest()
+	s.Equal(2, key)
+	s.Equal(0, s.lfu.Len())
+}
+
+func TestLfuTestSuite(t *testing.T) {
+	suite.Run(t, new(LfuTestSuite))
+}
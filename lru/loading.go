@@ -0,0 +1,203 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// Loader fetches the value of a key missing from the cache
+type Loader[k comparable, v any] func(ctx context.Context, key k) (v, error)
+
+// RefreshErrorFunc receives the errors of background refreshes
+type RefreshErrorFunc[k comparable] func(key k, err error)
+
+// LoadingCache is a thread-safe read-through cache. GetOrLoad calls the
+// loader once per missing key however many goroutines ask for it, and every
+// waiter gets the same value or error.
+type LoadingCache[k comparable, v any] struct {
+	cache          *SyncLru[k, v]
+	errors         *SyncLru[k, error]
+	loader         Loader[k, v]
+	batchLoader    BatchLoader[k, v]
+	group          group[k, v]
+	negativeTTL    time.Duration
+	clock          Clock
+	refreshAfter   time.Duration
+	onRefreshError RefreshErrorFunc[k]
+	// loadedAt and refreshing are guarded by the lock of cache
+	loadedAt   map[k]int64
+	refreshing map[k]struct{}
+}
+
+// NewLoadingCache returns a loading cache holding at most capacity entries,
+// capacity <= 0 means unbounded
+func NewLoadingCache[k comparable, v any](capacity int, loader Loader[k, v]) *LoadingCache[k, v] {
+	cache := &LoadingCache[k, v]{
+		errors:     NewSyncLru[k, error](capacity, nil),
+		loader:     loader,
+		clock:      SystemClock,
+		loadedAt:   make(map[k]int64),
+		refreshing: make(map[k]struct{}),
+	}
+	cache.cache = NewSyncLru(capacity, func(key k, value v, reason EvictReason) {
+		delete(cache.loadedAt, key)
+	})
+	return cache
+}
+
+// Cache returns the underlying cache, e.g. to set a default ttl or read
+// stats. Writes made through it skip the load bookkeeping, refresh-ahead
+// counts their age from the first GetOrLoad hit.
+func (cache *LoadingCache[k, v]) Cache() *SyncLru[k, v] {
+	return cache.cache
+}
+
+// SetNegativeTTL caches loader errors for ttl so a failing key does not hit
+// the backend on every call, ttl <= 0 disables it. Not safe to call
+// concurrently with GetOrLoad.
+func (cache *LoadingCache[k, v]) SetNegativeTTL(ttl time.Duration) {
+	cache.negativeTTL = ttl
+	if ttl <= 0 {
+		cache.errors.Clear()
+	}
+}
+
+// SetClock replaces the clock of the value and error caches
+func (cache *LoadingCache[k, v]) SetClock(clock Clock) {
+	if clock == nil {
+		clock = SystemClock
+	}
+	cache.clock = clock
+	cache.cache.SetClock(clock)
+	cache.errors.SetClock(clock)
+}
+
+// SetRefreshAfter enables refresh-ahead: GetOrLoad keeps returning a value
+// loaded more than refreshAfter ago but reloads it in the background. Hard
+// expiry still applies through the default ttl of Cache(), so refreshAfter
+// should be shorter than it. Values stored through Add or the loaders are
+// aged from their write, values written through Cache() from their first
+// GetOrLoad hit. refreshAfter <= 0 disables refreshing. Not safe to call
+// concurrently with GetOrLoad.
+func (cache *LoadingCache[k, v]) SetRefreshAfter(refreshAfter time.Duration) {
+	cache.refreshAfter = refreshAfter
+}
+
+// SetRefreshErrorHandler sets the callback receiving background refresh
+// errors, the stale value stays cached until it expires
+func (cache *LoadingCache[k, v]) SetRefreshErrorHandler(onRefreshError RefreshErrorFunc[k]) {
+	cache.onRefreshError = onRefreshError
+}
+
+// GetOrLoad returns the cached value of key or loads it. The loader gets a
+// context carrying the values of ctx but not its cancellation, since other
+// callers may wait for the same load; ctx only bounds how long this caller
+// waits.
+func (cache *LoadingCache[k, v]) GetOrLoad(ctx context.Context, key k) (v, error) {
+	cache.cache.lock.Lock()
+	value, ok := cache.cache.lru.Get(key)
+	refresh := ok && cache.refreshDue(key)
+	cache.cache.lock.Unlock()
+	if ok {
+		if refresh {
+			go cache.refresh(detachedContext{ctx}, key)
+		}
+		return value, nil
+	}
+	if cache.negativeTTL > 0 {
+		if err, ok := cache.errors.Peek(key); ok {
+			return *new(v), err
+		}
+	}
+	return cache.group.do(ctx, key, func() (v, error) {
+		// a load finishing between our miss and now already filled it
+		if value, ok := cache.cache.Peek(key); ok {
+			return value, nil
+		}
+		return cache.load(detachedContext{ctx}, key)
+	})
+}
+
+func (cache *LoadingCache[k, v]) load(ctx context.Context, key k) (v, error) {
+	value, err := cache.loader(ctx, key)
+	if err != nil {
+		if cache.negativeTTL > 0 {
+			cache.errors.AddWithTTL(key, err, cache.negativeTTL)
+		}
+		return value, err
+	}
+	cache.store(key, value)
+	cache.errors.Remove(key)
+	return value, nil
+}
+
+// store adds the value and records when it was loaded
+func (cache *LoadingCache[k, v]) store(key k, value v) (overwrite bool) {
+	cache.cache.lock.Lock()
+	defer cache.cache.lock.Unlock()
+	overwrite = cache.cache.lru.Add(key, value)
+	cache.loadedAt[key] = cache.clock.Now().UnixNano()
+	return overwrite
+}
+
+// refreshDue tells if a cached key is old enough to be reloaded and marks it
+// as refreshing, called with the cache lock held
+func (cache *LoadingCache[k, v]) refreshDue(key k) bool {
+	if cache.refreshAfter <= 0 {
+		return false
+	}
+	if _, ok := cache.refreshing[key]; ok {
+		return false
+	}
+	now := cache.clock.Now().UnixNano()
+	loadedAt, ok := cache.loadedAt[key]
+	if !ok {
+		// written through Cache(), its age starts now
+		cache.loadedAt[key] = now
+		return false
+	}
+	if now-loadedAt < int64(cache.refreshAfter) {
+		return false
+	}
+	cache.refreshing[key] = struct{}{}
+	return true
+}
+
+// refresh reloads key, joining a load already in flight for it
+func (cache *LoadingCache[k, v]) refresh(ctx context.Context, key k) {
+	_, err := cache.group.do(ctx, key, func() (v, error) {
+		return cache.load(ctx, key)
+	})
+	cache.cache.lock.Lock()
+	delete(cache.refreshing, key)
+	cache.cache.lock.Unlock()
+	if err != nil && cache.onRefreshError != nil {
+		cache.onRefreshError(key, err)
+	}
+}
+
+// Get returns the cached value of key without loading it
+func (cache *LoadingCache[k, v]) Get(key k) (value v, exist bool) {
+	return cache.cache.Get(key)
+}
+
+// Add stores a value directly and forgets a cached error of key
+func (cache *LoadingCache[k, v]) Add(key k, value v) (overwrite bool) {
+	cache.errors.Remove(key)
+	return cache.store(key, value)
+}
+
+// Remove drops the value and any cached error of key
+func (cache *LoadingCache[k, v]) Remove(key k) (exist bool) {
+	cache.errors.Remove(key)
+	return cache.cache.Remove(key)
+}
+
+func (cache *LoadingCache[k, v]) Clear() {
+	cache.errors.Clear()
+	cache.cache.Clear()
+}
+
+func (cache *LoadingCache[k, v]) Len() int {
+	return cache.cache.Len()
+}
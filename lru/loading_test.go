@@ -0,0 +1,243 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadingCacheTestSuite struct {
+	suite.Suite
+	loads int32
+	err   error
+	gate  chan struct{}
+	// entered receives a signal when a load starts, before it waits on gate
+	entered chan struct{}
+	cache   *LoadingCache[int, string]
+}
+
+func (s *LoadingCacheTestSuite) SetupTest() {
+	s.loads = 0
+	s.err = nil
+	s.gate = nil
+	s.entered = make(chan struct{}, 16)
+	s.cache = NewLoadingCache(16, func(ctx context.Context, key int) (string, error) {
+		atomic.AddInt32(&s.loads, 1)
+		select {
+		case s.entered <- struct{}{}:
+		default:
+		}
+		if s.gate != nil {
+			<-s.gate
+		}
+		if s.err != nil {
+			return "", s.err
+		}
+		return fmt.Sprintf("value-%v", key), nil
+	})
+}
+
+func (s *LoadingCacheTestSuite) TestLoadOnce() {
+	s.gate = make(chan struct{})
+	var ready, wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		ready.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ready.Done()
+			value, err := s.cache.GetOrLoad(context.Background(), 1)
+			s.Nil(err)
+			s.Equal("value-1", value)
+		}()
+	}
+	// callers coming after the load find the value cached
+	ready.Wait()
+	<-s.entered
+	close(s.gate)
+	wg.Wait()
+	s.Equal(int32(1), atomic.LoadInt32(&s.loads))
+
+	value, ok := s.cache.Get(1)
+	s.True(ok)
+	s.Equal("value-1", value)
+}
+
+func (s *LoadingCacheTestSuite) TestSharedError() {
+	s.err = errors.New("backend down")
+	s.gate = make(chan struct{})
+	var ready, wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		ready.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ready.Done()
+			_, err := s.cache.GetOrLoad(context.Background(), 1)
+			s.Equal(s.err, err)
+		}()
+	}
+	ready.Wait()
+	<-s.entered
+	close(s.gate)
+	wg.Wait()
+	s.Equal(int32(1), atomic.LoadInt32(&s.loads))
+
+	// errors are not cached by default
+	s.cache.GetOrLoad(context.Background(), 1)
+	s.Equal(int32(2), atomic.LoadInt32(&s.loads))
+	s.Equal(0, s.cache.Len())
+}
+
+func (s *LoadingCacheTestSuite) TestNegativeTTL() {
+	clock := newFakeClock()
+	s.cache.SetClock(clock)
+	s.cache.SetNegativeTTL(time.Second)
+	s.err = errors.New("not found")
+
+	for i := 0; i < 3; i++ {
+		_, err := s.cache.GetOrLoad(context.Background(), 1)
+		s.Equal(s.err, err)
+	}
+	s.Equal(int32(1), s.loads)
+
+	clock.Advance(time.Second)
+	s.err = nil
+	value, err := s.cache.GetOrLoad(context.Background(), 1)
+	s.Nil(err)
+	s.Equal("value-1", value)
+	s.Equal(int32(2), s.loads)
+}
+
+func (s *LoadingCacheTestSuite) TestWaiterCancel() {
+	s.gate = make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.cache.GetOrLoad(ctx, 1)
+	s.ErrorIs(err, context.DeadlineExceeded)
+
+	// the load goes on for the others
+	close(s.gate)
+	value, err := s.cache.GetOrLoad(context.Background(), 1)
+	s.Nil(err)
+	s.Equal("value-1", value)
+	s.Equal(int32(1), atomic.LoadInt32(&s.loads))
+}
+
+func (s *LoadingCacheTestSuite) TestLoaderPanic() {
+	cache := NewLoadingCache(16, func(ctx context.Context, key int) (string, error) {
+		panic("loader bug")
+	})
+	_, err := cache.GetOrLoad(context.Background(), 1)
+	var panicErr *PanicError
+	s.ErrorAs(err, &panicErr)
+	s.Equal("loader bug", panicErr.Value)
+	s.NotEmpty(panicErr.Stack)
+	s.Equal(0, cache.Len())
+
+	// the call is over, the next load runs again
+	_, err = cache.GetOrLoad(context.Background(), 1)
+	s.ErrorAs(err, &panicErr)
+}
+
+func TestLoadingCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(LoadingCacheTestSuite))
+}
+
+type RefreshTestSuite struct {
+	suite.Suite
+	clock         *fakeClock
+	version       int32
+	err           atomic.Value
+	gate          chan struct{}
+	refreshErrors chan error
+	cache         *LoadingCache[string, int32]
+}
+
+func (s *RefreshTestSuite) SetupTest() {
+	s.clock = newFakeClock()
+	s.version = 0
+	s.err = atomic.Value{}
+	s.gate = make(chan struct{}, 16)
+	s.refreshErrors = make(chan error, 16)
+	s.cache = NewLoadingCache(16, func(ctx context.Context, key string) (int32, error) {
+		<-s.gate
+		if err, ok := s.err.Load().(error); ok {
+			return 0, err
+		}
+		return atomic.AddInt32(&s.version, 1), nil
+	})
+	s.cache.SetClock(s.clock)
+	s.cache.Cache().SetDefaultTTL(10 * time.Second)
+	s.cache.SetRefreshAfter(time.Second)
+	s.cache.SetRefreshErrorHandler(func(key string, err error) {
+		s.refreshErrors <- err
+	})
+}
+
+func (s *RefreshTestSuite) get() int32 {
+	value, err := s.cache.GetOrLoad(context.Background(), "flag")
+	s.Nil(err)
+	return value
+}
+
+func (s *RefreshTestSuite) TestStaleWhileRevalidate() {
+	s.gate <- struct{}{}
+	s.Equal(int32(1), s.get())
+
+	s.clock.Advance(500 * time.Millisecond)
+	s.Equal(int32(1), s.get())
+
+	// the stale value is served while the reload waits on the gate
+	s.clock.Advance(time.Second)
+	s.Equal(int32(1), s.get())
+	s.Equal(int32(1), s.get())
+	s.gate <- struct{}{}
+	s.Eventually(func() bool {
+		return s.get() == 2
+	}, time.Second, time.Millisecond)
+	s.Equal(int32(2), atomic.LoadInt32(&s.version))
+}
+
+func (s *RefreshTestSuite) TestRefreshError() {
+	s.gate <- struct{}{}
+	s.Equal(int32(1), s.get())
+
+	failure := errors.New("backend down")
+	s.err.Store(failure)
+	s.clock.Advance(2 * time.Second)
+	s.gate <- struct{}{}
+	s.Equal(int32(1), s.get())
+	s.Equal(failure, <-s.refreshErrors)
+	s.gate <- struct{}{}
+	s.Equal(int32(1), s.get())
+	s.Equal(failure, <-s.refreshErrors)
+
+	// hard expiry makes the error visible
+	s.clock.Advance(10 * time.Second)
+	s.gate <- struct{}{}
+	_, err := s.cache.GetOrLoad(context.Background(), "flag")
+	s.Equal(failure, err)
+}
+
+func (s *RefreshTestSuite) TestDirectWrite() {
+	s.cache.Cache().AddMany([]Entry[string, int32]{{"flag", 100}})
+	s.Equal(int32(100), s.get())
+
+	s.clock.Advance(2 * time.Second)
+	s.gate <- struct{}{}
+	s.Equal(int32(100), s.get())
+	s.Eventually(func() bool {
+		return s.get() == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestRefreshTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTestSuite))
+}
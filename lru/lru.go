@@ -1,9 +1,15 @@
 package lru
 
+import "time"
+
 type Node[k comparable, v any] struct {
 	pre, nxt *Node[k, v]
 	value    v
 	key      k
+	// expire is the unix nano deadline of the entry, 0 means never
+	expire int64
+	// pins counts Pin calls not yet released by Unpin
+	pins int
 }
 
 type List[k comparable, v any] struct {
@@ -21,6 +27,7 @@ type IList[k comparable, v any] interface {
 	MoveToFront(*Node[k, v])
 	Len() int
 	Iterate(iterateFunc IterateFunc[k, v])
+	IterateReverse(iterateFunc IterateFunc[k, v])
 }
 
 func NewList[k comparable, v any]() *List[k, v] {
@@ -78,30 +85,123 @@ func (list *List[k, v]) MoveToFront(node *Node[k, v]) {
 	list.head.nxt = node
 }
 
+// pushFront links a node detached by Remove, possibly from another list,
+// at the head without allocating
+func (list *List[k, v]) pushFront(node *Node[k, v]) {
+	node.pre = list.head
+	node.nxt = list.head.nxt
+	node.nxt.pre = node
+	list.head.nxt = node
+	list.size += 1
+}
+
 func (list *List[k, v]) Remove(node *Node[k, v]) {
 	node.pre.nxt = node.nxt
 	node.nxt.pre = node.pre
 	list.size -= 1
 }
 
+// Back returns the oldest node, or nil if list is empty
+func (list *List[k, v]) Back() *Node[k, v] {
+	if list.size == 0 {
+		return nil
+	}
+	return list.tail.pre
+}
+
 func (list *List[k, v]) Len() int {
 	return list.size
 }
 
 func (list *List[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
-	for node := list.head.nxt; node != nil && node != list.tail; node = node.nxt {
-		if iterateFunc(node.key, node.value) {
+	list.walk(false, func(node *Node[k, v]) bool {
+		return iterateFunc(node.key, node.value)
+	})
+}
+
+// IterateReverse walks the list from tail to head
+func (list *List[k, v]) IterateReverse(iterateFunc IterateFunc[k, v]) {
+	list.walk(true, func(node *Node[k, v]) bool {
+		return iterateFunc(node.key, node.value)
+	})
+}
+
+// walk visits nodes from head to tail, or from tail to head if reverse.
+// visit may remove the visited node but no other.
+func (list *List[k, v]) walk(reverse bool, visit func(node *Node[k, v]) (stop bool)) {
+	if reverse {
+		for node := list.tail.pre; node != nil && node != list.head; {
+			pre := node.pre
+			if visit(node) {
+				return
+			}
+			node = pre
+		}
+		return
+	}
+	for node := list.head.nxt; node != nil && node != list.tail; {
+		nxt := node.nxt
+		if visit(node) {
 			return
 		}
+		node = nxt
+	}
+}
+
+// EvictReason tells why an entry left the cache
+type EvictReason int
+
+const (
+	// EvictReasonCapacity: entry dropped from the list tail to respect capacity
+	EvictReasonCapacity EvictReason = iota
+	// EvictReasonRemoved: entry removed explicitly by caller
+	EvictReasonRemoved
+	// EvictReasonCleared: entry dropped by Clear
+	EvictReasonCleared
+	// EvictReasonExpired: entry outlived its ttl
+	EvictReasonExpired
+
+	evictReasonCount
+)
+
+func (reason EvictReason) String() string {
+	switch reason {
+	case EvictReasonCapacity:
+		return "capacity"
+	case EvictReasonRemoved:
+		return "removed"
+	case EvictReasonCleared:
+		return "cleared"
+	case EvictReasonExpired:
+		return "expired"
 	}
+	return "unknown"
 }
 
+// EvictFunc is called for every entry leaving the cache
+type EvictFunc[k comparable, v any] func(key k, value v, reason EvictReason)
+
 // Lru implements a non-thread-safe lib of lru cache
 type Lru[k comparable, v any] struct {
-	list *List[k, v]
-	hash map[k]*Node[k, v]
+	list     *List[k, v]
+	hash     map[k]*Node[k, v]
+	capacity int
+	onEvict  EvictFunc[k, v]
+	ttl      time.Duration
+	clock    Clock
+	stats    *statsCounter
+	// tags indexes keys by tag and keyTags tags by key, both are created on
+	// first use
+	tags    map[string]map[k]struct{}
+	keyTags map[k][]string
+	// pinned counts entries with pins
+	pinned      int
+	subscribers []*Subscription[k, v]
 }
 
+// ILru is the common interface of caches in this package.
+// Iterate walks entries from most to least recently used, IterateList walks
+// them in eviction order, i.e. the order RemoveOldest would pop them.
 type ILru[k comparable, v any] interface {
 	Add(key k, value v) (overwrite bool)
 	Get(key k) (value v, exist bool)
@@ -113,49 +213,204 @@ type ILru[k comparable, v any] interface {
 	IterateList(iterateFunc IterateFunc[k, v])
 }
 
+var _ ILru[int, int] = (*Lru[int, int])(nil)
+
+// NewLru returns an unbounded lru, entries leave it only by Remove or Clear
 func NewLru[k comparable, v any]() *Lru[k, v] {
+	return NewLruWithCapacity[k, v](0, nil)
+}
+
+// NewLruWithCapacity returns a lru holding at most capacity entries, the
+// oldest entry is evicted by Add once the capacity is exceeded.
+// capacity <= 0 means unbounded, onEvict is optional.
+func NewLruWithCapacity[k comparable, v any](capacity int, onEvict EvictFunc[k, v]) *Lru[k, v] {
+	if capacity < 0 {
+		capacity = 0
+	}
 	return &Lru[k, v]{
-		list: NewList[k, v](),
-		hash: make(map[k]*Node[k, v]),
+		list:     NewList[k, v](),
+		hash:     make(map[k]*Node[k, v]),
+		capacity: capacity,
+		onEvict:  onEvict,
+		clock:    SystemClock,
+		stats:    &statsCounter{},
 	}
 }
 
-func (lru *Lru[k, v]) Add(key k, value v) {
+// Add inserts or replaces the value of key and marks it most recently used,
+// the entry gets the default ttl if any
+func (lru *Lru[k, v]) Add(key k, value v) (overwrite bool) {
+	return lru.add(key, value, lru.ttl)
+}
+
+func (lru *Lru[k, v]) add(key k, value v, ttl time.Duration) (overwrite bool) {
 	if node, ok := lru.hash[key]; ok {
-		lru.list.MoveToFront(node)
-	} else {
-		lru.hash[key] = lru.list.Prepend(key, value)
+		if !lru.expired(node) {
+			node.value = value
+			node.expire = lru.deadline(ttl)
+			lru.list.MoveToFront(node)
+			lru.stats.update()
+			lru.publish(EventUpdated, key, value, 0)
+			return true
+		}
+		lru.removeNode(node, EvictReasonExpired)
+	}
+	lru.stats.insert()
+	node := lru.list.Prepend(key, value)
+	node.expire = lru.deadline(ttl)
+	lru.hash[key] = node
+	lru.publish(EventAdded, key, value, 0)
+	lru.trim()
+	return false
+}
+
+// trim evicts from the list tail until capacity fits, pinned entries are
+// skipped so the lru may stay over capacity until they are unpinned
+func (lru *Lru[k, v]) trim() (evicted int) {
+	if lru.capacity <= 0 {
+		return 0
+	}
+	node := lru.list.Back()
+	for lru.list.Len() > lru.capacity && node != nil && node != lru.list.head {
+		pre := node.pre
+		if node.pins == 0 {
+			if lru.expired(node) {
+				lru.removeNode(node, EvictReasonExpired)
+			} else {
+				lru.removeNode(node, EvictReasonCapacity)
+			}
+			evicted++
+		}
+		node = pre
+	}
+	return evicted
+}
+
+func (lru *Lru[k, v]) removeNode(node *Node[k, v], reason EvictReason) {
+	lru.list.Remove(node)
+	delete(lru.hash, node.key)
+	if node.pins > 0 {
+		lru.pinned--
 	}
+	lru.stats.evict(reason, 1)
+	lru.untag(node.key)
+	if lru.onEvict != nil {
+		lru.onEvict(node.key, node.value, reason)
+	}
+	lru.publishEvict(node.key, node.value, reason)
 }
 
+// Get returns the value of key and marks it most recently used, an expired
+// entry is removed and reported as missing
 func (lru *Lru[k, v]) Get(key k) (value v, exist bool) {
 	if node, ok := lru.hash[key]; ok {
+		if lru.expired(node) {
+			lru.removeNode(node, EvictReasonExpired)
+			lru.stats.miss()
+			return value, false
+		}
 		lru.list.MoveToFront(node)
+		lru.stats.hit()
 		return node.value, true
 	}
+	lru.stats.miss()
 	var temp v
 	return temp, false
 }
 
+// Peek returns the value of key without updating its recency, an expired
+// entry is reported as missing but left for Get or RemoveExpired to drop
+func (lru *Lru[k, v]) Peek(key k) (value v, exist bool) {
+	if node, ok := lru.hash[key]; ok && !lru.expired(node) {
+		return node.value, true
+	}
+	return value, false
+}
+
+// Contains checks key without updating its recency
+func (lru *Lru[k, v]) Contains(key k) bool {
+	node, ok := lru.hash[key]
+	return ok && !lru.expired(node)
+}
+
 func (lru *Lru[k, v]) Remove(key k) (exist bool) {
 	if node, ok := lru.hash[key]; ok {
-		lru.list.Remove(node)
-		delete(lru.hash, key)
+		lru.removeNode(node, EvictReasonRemoved)
 		return true
 	}
 	return false
 }
 
+// RemoveOldest pops the least recently used entry which is not pinned, zero
+// values are returned if there is none. Expired entries met on the way are
+// dropped.
+func (lru *Lru[k, v]) RemoveOldest() (key k, value v) {
+	key, value, _ = lru.removeOldest()
+	return
+}
+
+// removeOldest works as RemoveOldest, removed tells if an entry was popped
+func (lru *Lru[k, v]) removeOldest() (key k, value v, removed bool) {
+	for node := lru.list.Back(); node != nil && node != lru.list.head; {
+		pre := node.pre
+		if lru.expired(node) {
+			lru.removeNode(node, EvictReasonExpired)
+		} else if node.pins == 0 {
+			lru.removeNode(node, EvictReasonRemoved)
+			return node.key, node.value, true
+		}
+		node = pre
+	}
+	return
+}
+
 func (lru *Lru[k, v]) Clear() {
+	list := lru.list
 	// gc will recycle it
 	lru.hash = make(map[k]*Node[k, v])
+	lru.tags = nil
+	lru.keyTags = nil
+	lru.pinned = 0
 	lru.list = NewList[k, v]()
+	lru.stats.evict(EvictReasonCleared, list.Len())
+	if lru.onEvict != nil || len(lru.subscribers) > 0 {
+		list.Iterate(func(key k, value v) bool {
+			if lru.onEvict != nil {
+				lru.onEvict(key, value, EvictReasonCleared)
+			}
+			lru.publishEvict(key, value, EvictReasonCleared)
+			return false
+		})
+	}
+}
+
+// Cap returns the max entry count, 0 means unbounded
+func (lru *Lru[k, v]) Cap() int {
+	return lru.capacity
 }
 
+// Len counts entries including the expired ones not dropped yet
 func (lru *Lru[k, v]) Len() int {
 	return lru.list.Len()
 }
 
+// Iterate walks from most to least recently used, skipping expired entries.
+// iterateFunc may remove the visited key but must not change other entries.
 func (lru *Lru[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
-	lru.list.Iterate(iterateFunc)
+	lru.iterate(false, iterateFunc)
+}
+
+// IterateList walks from least to most recently used, skipping expired entries
+func (lru *Lru[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	lru.iterate(true, iterateFunc)
+}
+
+func (lru *Lru[k, v]) iterate(reverse bool, iterateFunc IterateFunc[k, v]) {
+	now := lru.clock.Now().UnixNano()
+	lru.list.walk(reverse, func(node *Node[k, v]) bool {
+		if node.expire != 0 && now >= node.expire {
+			return false
+		}
+		return iterateFunc(node.key, node.value)
+	})
 }
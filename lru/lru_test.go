@@ -39,6 +39,63 @@ func (s *LruTestSuite) TestAddGet() {
 	s.lru.Iterate(lru_print)
 }
 
+func (s *LruTestSuite) TestCapacityEvict() {
+	type evicted struct {
+		key    int
+		reason EvictReason
+	}
+	var evicts []evicted
+	lru := NewLruWithCapacity(2, func(key int, value string, reason EvictReason) {
+		evicts = append(evicts, evicted{key, reason})
+	})
+	s.Equal(2, lru.Cap())
+
+	lru.Add(1, "one")
+	lru.Add(2, "two")
+	lru.Get(1)
+	lru.Add(3, "three")
+	s.Equal(2, lru.Len())
+	_, ok := lru.Get(2)
+	s.False(ok)
+	s.Equal([]evicted{{2, EvictReasonCapacity}}, evicts)
+
+	s.True(lru.Remove(1))
+	s.False(lru.Remove(1))
+	lru.Clear()
+	s.Equal(0, lru.Len())
+	s.Equal([]evicted{
+		{2, EvictReasonCapacity},
+		{1, EvictReasonRemoved},
+		{3, EvictReasonCleared},
+	}, evicts)
+}
+
+func (s *LruTestSuite) TestOrder() {
+	for i := 0; i < 4; i++ {
+		s.lru.Add(i, fmt.Sprintf("I'm %v", i))
+	}
+	s.True(s.lru.Add(1, "updated"))
+
+	var keys []int
+	s.lru.Iterate(func(key int, value string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	s.Equal([]int{1, 3, 2, 0}, keys)
+
+	keys = keys[:0]
+	s.lru.IterateList(func(key int, value string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	s.Equal([]int{0, 2, 3, 1}, keys)
+
+	key, value := s.lru.RemoveOldest()
+	s.Equal(0, key)
+	s.Equal("I'm 0", value)
+	s.Equal(3, s.lru.Len())
+}
+
 func TestLruTestSuite(t *testing.T) {
 	suite.Run(t, new(LruTestSuite))
 }
@@ -0,0 +1,276 @@
+package lru
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// DefaultLruK is the K of NewLruK when depth < 1, LRU-2 is the usual choice
+const DefaultLruK = 2
+
+type lrukEntry[k comparable, v any] struct {
+	key   k
+	value v
+	// history holds the last K access times, most recent first, 0 means
+	// no access
+	history []uint64
+	// index is the position of the entry in the heap
+	index int
+}
+
+// kth returns the Kth most recent access time, 0 if the entry has fewer
+// than K accesses
+func (entry *lrukEntry[k, v]) kth() uint64 {
+	return entry.history[len(entry.history)-1]
+}
+
+// lrukHeap orders entries by eviction priority, the victim is at the top
+type lrukHeap[k comparable, v any] []*lrukEntry[k, v]
+
+func (h lrukHeap[k, v]) Len() int {
+	return len(h)
+}
+
+func (h lrukHeap[k, v]) Less(i, j int) bool {
+	return lrukLess(h[i], h[j])
+}
+
+// lrukLess puts entries with fewer than K accesses first, then the oldest
+// Kth access, ties are broken by the oldest last access
+func lrukLess[k comparable, v any](a, b *lrukEntry[k, v]) bool {
+	if a.kth() != b.kth() {
+		return a.kth() < b.kth()
+	}
+	return a.history[0] < b.history[0]
+}
+
+func (h lrukHeap[k, v]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lrukHeap[k, v]) Push(x any) {
+	entry := x.(*lrukEntry[k, v])
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *lrukHeap[k, v]) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// LruK implements a non-thread-safe LRU-K cache: it keeps the last K access
+// times of every entry and evicts the one whose Kth most recent access is
+// the oldest, entries accessed fewer than K times go first. Access times
+// are ticks of a logical clock counting Add and Get calls. The history of
+// evicted keys is retained for the last capacity of them, so a key coming
+// back soon gets its earlier accesses counted.
+type LruK[k comparable, v any] struct {
+	k        int
+	capacity int
+	tick     uint64
+	hash     map[k]*lrukEntry[k, v]
+	heap     lrukHeap[k, v]
+	// retained maps evicted keys to their history, retainedList orders
+	// them from the most recently evicted
+	retained     map[k]*Node[k, []uint64]
+	retainedList *List[k, []uint64]
+	onEvict      EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*LruK[int, int])(nil)
+
+// NewLruK returns a LRU-K cache holding at most capacity entries, depth is
+// the K, depth < 1 means DefaultLruK and capacity <= 0 means unbounded
+func NewLruK[k comparable, v any](capacity int, depth int, onEvict EvictFunc[k, v]) *LruK[k, v] {
+	if capacity < 0 {
+		capacity = 0
+	}
+	if depth < 1 {
+		depth = DefaultLruK
+	}
+	return &LruK[k, v]{
+		k:            depth,
+		capacity:     capacity,
+		hash:         make(map[k]*lrukEntry[k, v]),
+		retained:     make(map[k]*Node[k, []uint64]),
+		retainedList: NewList[k, []uint64](),
+		onEvict:      onEvict,
+	}
+}
+
+// access records an access to entry now
+func (lruk *LruK[k, v]) access(entry *lrukEntry[k, v]) {
+	lruk.tick++
+	copy(entry.history[1:], entry.history)
+	entry.history[0] = lruk.tick
+}
+
+// Add inserts or replaces the value of key, both count as an access. A new
+// key evicts the victim first so it is never evicted by its own insertion.
+func (lruk *LruK[k, v]) Add(key k, value v) (overwrite bool) {
+	if entry, ok := lruk.hash[key]; ok {
+		entry.value = value
+		lruk.access(entry)
+		heap.Fix(&lruk.heap, entry.index)
+		return true
+	}
+	if lruk.capacity > 0 && len(lruk.hash) >= lruk.capacity {
+		lruk.removeEntry(lruk.heap[0], EvictReasonCapacity)
+	}
+	entry := &lrukEntry[k, v]{key: key, value: value}
+	if node, ok := lruk.retained[key]; ok {
+		entry.history = node.value
+		lruk.retainedList.Remove(node)
+		delete(lruk.retained, key)
+	} else {
+		entry.history = make([]uint64, lruk.k)
+	}
+	lruk.access(entry)
+	lruk.hash[key] = entry
+	heap.Push(&lruk.heap, entry)
+	return false
+}
+
+func (lruk *LruK[k, v]) Get(key k) (value v, exist bool) {
+	if entry, ok := lruk.hash[key]; ok {
+		lruk.access(entry)
+		heap.Fix(&lruk.heap, entry.index)
+		return entry.value, true
+	}
+	return value, false
+}
+
+// Peek returns the value of key without counting an access
+func (lruk *LruK[k, v]) Peek(key k) (value v, exist bool) {
+	if entry, ok := lruk.hash[key]; ok {
+		return entry.value, true
+	}
+	return value, false
+}
+
+func (lruk *LruK[k, v]) Contains(key k) bool {
+	_, ok := lruk.hash[key]
+	return ok
+}
+
+// History returns the retained access times of key, most recent first, for
+// a cached or recently evicted key
+func (lruk *LruK[k, v]) History(key k) []uint64 {
+	var history []uint64
+	if entry, ok := lruk.hash[key]; ok {
+		history = entry.history
+	} else if node, ok := lruk.retained[key]; ok {
+		history = node.value
+	}
+	var accesses []uint64
+	for _, tick := range history {
+		if tick != 0 {
+			accesses = append(accesses, tick)
+		}
+	}
+	return accesses
+}
+
+// Remove drops key along with its history
+func (lruk *LruK[k, v]) Remove(key k) (exist bool) {
+	if entry, ok := lruk.hash[key]; ok {
+		lruk.removeEntry(entry, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest pops the entry LruK would evict next
+func (lruk *LruK[k, v]) RemoveOldest() (key k, value v) {
+	if len(lruk.heap) == 0 {
+		return
+	}
+	entry := lruk.heap[0]
+	lruk.removeEntry(entry, EvictReasonRemoved)
+	return entry.key, entry.value
+}
+
+// removeEntry unlinks entry, the history of entries evicted for capacity
+// is retained
+func (lruk *LruK[k, v]) removeEntry(entry *lrukEntry[k, v], reason EvictReason) {
+	heap.Remove(&lruk.heap, entry.index)
+	delete(lruk.hash, entry.key)
+	if reason == EvictReasonCapacity {
+		lruk.retain(entry.key, entry.history)
+	}
+	if lruk.onEvict != nil {
+		lruk.onEvict(entry.key, entry.value, reason)
+	}
+}
+
+func (lruk *LruK[k, v]) retain(key k, history []uint64) {
+	lruk.retained[key] = lruk.retainedList.Prepend(key, history)
+	if lruk.capacity > 0 && lruk.retainedList.Len() > lruk.capacity {
+		oldest := lruk.retainedList.Back()
+		lruk.retainedList.Remove(oldest)
+		delete(lruk.retained, oldest.key)
+	}
+}
+
+// Clear drops every entry and the retained history
+func (lruk *LruK[k, v]) Clear() {
+	entries := lruk.heap
+	lruk.hash = make(map[k]*lrukEntry[k, v])
+	lruk.heap = nil
+	lruk.retained = make(map[k]*Node[k, []uint64])
+	lruk.retainedList = NewList[k, []uint64]()
+	if lruk.onEvict != nil {
+		for _, entry := range entries {
+			lruk.onEvict(entry.key, entry.value, EvictReasonCleared)
+		}
+	}
+}
+
+// K returns the number of accesses tracked per key
+func (lruk *LruK[k, v]) K() int {
+	return lruk.k
+}
+
+// Cap returns the max entry count, 0 means unbounded
+func (lruk *LruK[k, v]) Cap() int {
+	return lruk.capacity
+}
+
+func (lruk *LruK[k, v]) Len() int {
+	return len(lruk.hash)
+}
+
+// Iterate walks from the entry LruK would evict last to the next victim
+func (lruk *LruK[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	entries := lruk.sorted()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if iterateFunc(entries[i].key, entries[i].value) {
+			return
+		}
+	}
+}
+
+// IterateList walks in eviction order
+func (lruk *LruK[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	for _, entry := range lruk.sorted() {
+		if iterateFunc(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// sorted returns a copy of the entries in eviction order
+func (lruk *LruK[k, v]) sorted() lrukHeap[k, v] {
+	entries := make(lrukHeap[k, v], len(lruk.heap))
+	copy(entries, lruk.heap)
+	sort.Slice(entries, func(i, j int) bool {
+		return lrukLess(entries[i], entries[j])
+	})
+	return entries
+}
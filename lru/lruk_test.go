@@ -0,0 +1,98 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LruKTestSuite struct {
+	suite.Suite
+	lruk *LruK[int, string]
+}
+
+func (s *LruKTestSuite) SetupTest() {
+	s.lruk = NewLruK[int, string](3, 0, nil)
+	s.Equal(DefaultLruK, s.lruk.K())
+}
+
+func (s *LruKTestSuite) TestEvictOldestKth() {
+	s.lruk.Add(1, "one")
+	s.lruk.Add(2, "two")
+	s.lruk.Add(3, "three")
+	s.lruk.Get(1)
+	s.lruk.Get(2)
+
+	// 3 has a single access
+	s.lruk.Add(4, "four")
+	s.False(s.lruk.Contains(3))
+	s.lruk.Add(5, "five")
+	s.False(s.lruk.Contains(4))
+
+	// the retained history gives 3 its second access
+	s.lruk.Add(3, "three")
+	s.False(s.lruk.Contains(5))
+	s.Equal([]uint64{8, 3}, s.lruk.History(3))
+	s.Equal([]uint64{7}, s.lruk.History(5))
+
+	// 1 has the oldest second most recent access
+	s.lruk.Add(6, "six")
+	s.False(s.lruk.Contains(1))
+	s.Equal([]int{6, 2, 3}, s.keys())
+}
+
+func (s *LruKTestSuite) keys() []int {
+	var keys []int
+	s.lruk.IterateList(func(key int, value string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	return keys
+}
+
+func (s *LruKTestSuite) TestScanResistance() {
+	lruk := NewLruK[int, int](4, 2, nil)
+	for i := 0; i < 2; i++ {
+		lruk.Add(1, 1)
+		lruk.Add(2, 2)
+	}
+	for i := 10; i < 30; i++ {
+		lruk.Add(i, i)
+	}
+	s.True(lruk.Contains(1))
+	s.True(lruk.Contains(2))
+	// the retained table is bounded by capacity
+	s.Equal(4, lruk.retainedList.Len())
+	s.Nil(lruk.History(10))
+}
+
+func (s *LruKTestSuite) TestLruK1() {
+	lruk := NewLruK[int, int](2, 1, nil)
+	lruk.Add(1, 1)
+	lruk.Add(2, 2)
+	lruk.Get(1)
+	lruk.Add(3, 3)
+	s.False(lruk.Contains(2))
+	key, _ := lruk.RemoveOldest()
+	s.Equal(1, key)
+}
+
+func (s *LruKTestSuite) TestRemove() {
+	var evicted []EvictReason
+	lruk := NewLruK(2, 2, func(key int, value string, reason EvictReason) {
+		evicted = append(evicted, reason)
+	})
+	lruk.Add(1, "one")
+	lruk.Add(2, "two")
+	lruk.Add(3, "three")
+	s.True(lruk.Remove(2))
+	s.Nil(lruk.History(2))
+	s.Equal([]uint64{1}, lruk.History(1))
+	lruk.Clear()
+	s.Nil(lruk.History(1))
+	s.Equal([]EvictReason{EvictReasonCapacity, EvictReasonRemoved, EvictReasonCleared}, evicted)
+}
+
+func TestLruKTestSuite(t *testing.T) {
+	suite.Run(t, new(LruKTestSuite))
+}
@@ -0,0 +1,127 @@
+// Package lrutest holds tests shared by the cache implementations of lru
+// and usable by implementations of lru.ILru outside of it.
+package lrutest
+
+import (
+	"fmt"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/yixiaoyang/simpelib/lru"
+)
+
+// ConformanceCapacity is the capacity the suite passes to New
+const ConformanceCapacity = 8
+
+// ILruConformanceSuite checks the behaviors every ILru implementation must
+// share regardless of its eviction policy. Run it from a Test function:
+//
+//	suite.Run(t, &ILruConformanceSuite{New: func(capacity int) lru.ILru[int, string] {
+//		return lru.NewLruWithCapacity[int, string](capacity, nil)
+//	}})
+type ILruConformanceSuite struct {
+	suite.Suite
+	New   func(capacity int) lru.ILru[int, string]
+	cache lru.ILru[int, string]
+}
+
+func (s *ILruConformanceSuite) SetupTest() {
+	s.cache = s.New(ConformanceCapacity)
+	s.NotNil(s.cache)
+}
+
+func conformanceValue(key int) string {
+	return fmt.Sprintf("value-%v", key)
+}
+
+func (s *ILruConformanceSuite) TestAddOverwrite() {
+	s.False(s.cache.Add(1, "first"))
+	value, ok := s.cache.Get(1)
+	s.True(ok)
+	s.Equal("first", value)
+
+	s.True(s.cache.Add(1, "second"))
+	value, ok = s.cache.Get(1)
+	s.True(ok)
+	s.Equal("second", value)
+	s.Equal(1, s.cache.Len())
+
+	_, ok = s.cache.Get(2)
+	s.False(ok)
+}
+
+func (s *ILruConformanceSuite) TestRemove() {
+	s.cache.Add(1, conformanceValue(1))
+	s.True(s.cache.Remove(1))
+	s.False(s.cache.Remove(1))
+	_, ok := s.cache.Get(1)
+	s.False(ok)
+	s.Equal(0, s.cache.Len())
+}
+
+func (s *ILruConformanceSuite) TestRemoveOldest() {
+	key, value := s.cache.RemoveOldest()
+	s.Equal(0, key)
+	s.Equal("", value)
+
+	for i := 1; i <= 4; i++ {
+		s.cache.Add(i, conformanceValue(i))
+	}
+	for s.cache.Len() > 0 {
+		length := s.cache.Len()
+		var first int
+		s.cache.IterateList(func(key int, value string) bool {
+			first = key
+			return true
+		})
+		key, value := s.cache.RemoveOldest()
+		s.Equal(first, key)
+		s.Equal(conformanceValue(key), value)
+		s.Equal(length-1, s.cache.Len())
+		_, ok := s.cache.Get(key)
+		s.False(ok)
+	}
+}
+
+func (s *ILruConformanceSuite) TestClear() {
+	for i := 0; i < ConformanceCapacity; i++ {
+		s.cache.Add(i, conformanceValue(i))
+	}
+	s.cache.Clear()
+	s.Equal(0, s.cache.Len())
+	_, ok := s.cache.Get(0)
+	s.False(ok)
+
+	s.False(s.cache.Add(0, conformanceValue(0)))
+	s.Equal(1, s.cache.Len())
+}
+
+func (s *ILruConformanceSuite) TestCapacity() {
+	for i := 0; i < ConformanceCapacity*4; i++ {
+		s.cache.Add(i, conformanceValue(i))
+		s.cache.Get(i % 3)
+		s.LessOrEqual(s.cache.Len(), ConformanceCapacity)
+	}
+}
+
+func (s *ILruConformanceSuite) TestIterate() {
+	for i := 0; i < ConformanceCapacity*2; i++ {
+		s.cache.Add(i, conformanceValue(i))
+	}
+	for _, iterate := range []func(lru.IterateFunc[int, string]){s.cache.Iterate, s.cache.IterateList} {
+		seen := make(map[int]bool)
+		iterate(func(key int, value string) bool {
+			s.False(seen[key])
+			s.Equal(conformanceValue(key), value)
+			seen[key] = true
+			return false
+		})
+		s.Equal(s.cache.Len(), len(seen))
+
+		count := 0
+		iterate(func(key int, value string) bool {
+			count++
+			return true
+		})
+		s.Equal(1, count)
+	}
+}
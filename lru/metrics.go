@@ -0,0 +1,128 @@
+package lru
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StatsProvider is implemented by the caches keeping Stats
+type StatsProvider interface {
+	Stats() Stats
+}
+
+// MetricsHandler serves the stats of registered caches in the prometheus
+// text exposition format, every series is labelled with the cache name
+type MetricsHandler struct {
+	namespace string
+	lock      sync.RWMutex
+	caches    map[string]StatsProvider
+}
+
+var _ http.Handler = (*MetricsHandler)(nil)
+
+// NewMetricsHandler prefixes metric names with namespace, "lru" if empty
+func NewMetricsHandler(namespace string) *MetricsHandler {
+	if namespace == "" {
+		namespace = "lru"
+	}
+	return &MetricsHandler{
+		namespace: namespace,
+		caches:    make(map[string]StatsProvider),
+	}
+}
+
+// Register exposes cache under name, replacing a cache of the same name
+func (handler *MetricsHandler) Register(name string, cache StatsProvider) {
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	handler.caches[name] = cache
+}
+
+func (handler *MetricsHandler) Unregister(name string) {
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	delete(handler.caches, name)
+}
+
+func (handler *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writer := bufio.NewWriter(w)
+	handler.write(writer)
+	writer.Flush()
+}
+
+type namedStats struct {
+	label string
+	stats Stats
+}
+
+func (handler *MetricsHandler) snapshot() []namedStats {
+	handler.lock.RLock()
+	defer handler.lock.RUnlock()
+	snapshot := make([]namedStats, 0, len(handler.caches))
+	for name, cache := range handler.caches {
+		snapshot = append(snapshot, namedStats{
+			label: fmt.Sprintf(`cache="%s"`, escapeLabel(name)),
+			stats: cache.Stats(),
+		})
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].label < snapshot[j].label
+	})
+	return snapshot
+}
+
+func (handler *MetricsHandler) write(writer *bufio.Writer) {
+	snapshot := handler.snapshot()
+	family := func(name, kind, help string, value func(stats Stats) string) {
+		name = handler.namespace + "_" + name
+		fmt.Fprintf(writer, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, cache := range snapshot {
+			fmt.Fprintf(writer, "%s{%s} %s\n", name, cache.label, value(cache.stats))
+		}
+	}
+	counter := func(value uint64) string {
+		return fmt.Sprintf("%d", value)
+	}
+
+	family("hits_total", "counter", "Lookups that found an entry.", func(stats Stats) string {
+		return counter(stats.Hits)
+	})
+	family("misses_total", "counter", "Lookups that found no entry.", func(stats Stats) string {
+		return counter(stats.Misses)
+	})
+	family("inserts_total", "counter", "Entries added for a new key.", func(stats Stats) string {
+		return counter(stats.Inserts)
+	})
+	family("updates_total", "counter", "Entries replacing the value of a cached key.", func(stats Stats) string {
+		return counter(stats.Updates)
+	})
+
+	name := handler.namespace + "_evictions_total"
+	fmt.Fprintf(writer, "# HELP %s Entries that left the cache by reason.\n# TYPE %s counter\n", name, name)
+	for _, cache := range snapshot {
+		for reason, count := range cache.stats.Evictions {
+			fmt.Fprintf(writer, "%s{%s,reason=\"%s\"} %d\n", name, cache.label, EvictReason(reason), count)
+		}
+	}
+
+	family("entries", "gauge", "Entries currently cached.", func(stats Stats) string {
+		return fmt.Sprintf("%d", stats.Size)
+	})
+	family("pinned_entries", "gauge", "Entries currently pinned.", func(stats Stats) string {
+		return fmt.Sprintf("%d", stats.Pinned)
+	})
+	family("hit_ratio", "gauge", "Hits over lookups since the last reset.", func(stats Stats) string {
+		return fmt.Sprintf("%g", stats.HitRatio())
+	})
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
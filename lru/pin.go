@@ -0,0 +1,74 @@
+package lru
+
+// Pin protects a cached entry from eviction until a matching Unpin, pins
+// are counted so several holders may pin the same key. Pinned entries are
+// skipped by capacity eviction, Resize and RemoveOldest, so once every
+// cached entry is pinned a new entry is evicted as soon as it is added, and
+// a Resize below the pinned count leaves the lru over capacity until the
+// entries are unpinned. Expiry and explicit removals still apply to pinned
+// entries. It returns false if key is absent.
+func (lru *Lru[k, v]) Pin(key k) (exist bool) {
+	node, ok := lru.hash[key]
+	if !ok || lru.expired(node) {
+		return false
+	}
+	if node.pins == 0 {
+		lru.pinned++
+	}
+	node.pins++
+	return true
+}
+
+// Unpin releases one Pin of key, the entry may be evicted right away once
+// its last pin is released. It returns false if key is absent or not pinned.
+func (lru *Lru[k, v]) Unpin(key k) (exist bool) {
+	node, ok := lru.hash[key]
+	if !ok || node.pins == 0 {
+		return false
+	}
+	node.pins--
+	if node.pins == 0 {
+		lru.pinned--
+		lru.trim()
+	}
+	return true
+}
+
+// Pinned returns the number of pinned entries
+func (lru *Lru[k, v]) Pinned() int {
+	return lru.pinned
+}
+
+func (cache *SyncLru[k, v]) Pin(key k) (exist bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Pin(key)
+}
+
+func (cache *SyncLru[k, v]) Unpin(key k) (exist bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Unpin(key)
+}
+
+func (cache *SyncLru[k, v]) Pinned() int {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Pinned()
+}
+
+func (cache *ShardedLru[k, v]) Pin(key k) (exist bool) {
+	return cache.shard(key).Pin(key)
+}
+
+func (cache *ShardedLru[k, v]) Unpin(key k) (exist bool) {
+	return cache.shard(key).Unpin(key)
+}
+
+func (cache *ShardedLru[k, v]) Pinned() int {
+	pinned := 0
+	for _, shard := range cache.shards {
+		pinned += shard.Pinned()
+	}
+	return pinned
+}
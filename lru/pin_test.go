@@ -0,0 +1,122 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PinTestSuite struct {
+	suite.Suite
+	evicted []int
+	lru     *Lru[int, int]
+}
+
+func (s *PinTestSuite) SetupTest() {
+	s.evicted = nil
+	s.lru = NewLruWithCapacity(3, func(key int, value int, reason EvictReason) {
+		s.evicted = append(s.evicted, key)
+	})
+	for i := 1; i <= 3; i++ {
+		s.lru.Add(i, i)
+	}
+}
+
+func (s *PinTestSuite) TestSkipPinned() {
+	s.True(s.lru.Pin(1))
+	s.False(s.lru.Pin(9))
+	s.lru.Add(4, 4)
+	s.Equal([]int{2}, s.evicted)
+	s.True(s.lru.Contains(1))
+
+	key, _ := s.lru.RemoveOldest()
+	s.Equal(3, key)
+	s.Equal(1, s.lru.Pinned())
+	s.Equal(1, s.lru.Stats().Pinned)
+}
+
+func (s *PinTestSuite) TestOverCapacity() {
+	for i := 1; i <= 3; i++ {
+		s.lru.Pin(i)
+	}
+	s.lru.Pin(1)
+	// no room left for unpinned entries
+	s.False(s.lru.Add(4, 4))
+	s.Equal([]int{4}, s.evicted)
+	key, _ := s.lru.RemoveOldest()
+	s.Equal(0, key)
+
+	s.Equal(0, s.lru.Resize(2))
+	s.Equal(3, s.lru.Len())
+
+	// the last unpin lets the lru trim back to capacity
+	s.True(s.lru.Unpin(1))
+	s.Equal(3, s.lru.Len())
+	s.True(s.lru.Unpin(1))
+	s.False(s.lru.Unpin(1))
+	s.Equal([]int{4, 1}, s.evicted)
+	s.Equal(2, s.lru.Len())
+	s.Equal(2, s.lru.Pinned())
+}
+
+func (s *PinTestSuite) TestRemoval() {
+	clock := newFakeClock()
+	s.lru.SetClock(clock)
+	s.lru.AddWithTTL(4, 4, time.Second)
+	s.lru.Pin(4)
+	s.lru.Pin(3)
+	s.True(s.lru.Remove(3))
+	s.Equal(1, s.lru.Pinned())
+
+	// expiry applies to pinned entries
+	clock.Advance(time.Second)
+	s.Equal(1, s.lru.RemoveExpired())
+	s.Equal(0, s.lru.Pinned())
+	s.False(s.lru.Pin(4))
+
+	s.lru.Pin(2)
+	s.lru.Clear()
+	s.Equal(0, s.lru.Pinned())
+}
+
+func (s *PinTestSuite) TestSharded() {
+	cache := NewShardedLru[int, int](2, 8, nil, nil)
+	for i := 0; i < 4; i++ {
+		cache.Add(i, i)
+		cache.Pin(i)
+	}
+	cache.Resize(2)
+	s.Equal(4, cache.Len())
+	s.Equal(4, cache.Pinned())
+	for i := 0; i < 4; i++ {
+		s.True(cache.Unpin(i))
+	}
+	s.Equal(2, cache.Len())
+	s.Equal(0, cache.Stats().Pinned)
+}
+
+func (s *PinTestSuite) TestShardedRemoveOldest() {
+	cache := NewShardedLru[int, int](2, 8, nil, nil)
+	// pin a key of the first shard scanned, the other key lives in the next
+	pinned, other := 0, 0
+	for cache.shardIndex(pinned) != 0 {
+		pinned++
+	}
+	for cache.shardIndex(other) != 1 {
+		other++
+	}
+	cache.Add(pinned, pinned)
+	cache.Pin(pinned)
+	cache.Add(other, other)
+
+	key, _ := cache.RemoveOldest()
+	s.Equal(other, key)
+	cache.RemoveOldest()
+	s.Equal(1, cache.Len())
+	s.True(cache.Contains(pinned))
+}
+
+func TestPinTestSuite(t *testing.T) {
+	suite.Run(t, new(PinTestSuite))
+}
@@ -0,0 +1,97 @@
+package lru
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// policies lists the eviction policies compared by the hit ratio benchmarks
+var policies = []struct {
+	name string
+	new  func(capacity int) ILru[int, int]
+}{
+	{"Lru", func(capacity int) ILru[int, int] { return NewLruWithCapacity[int, int](capacity, nil) }},
+	{"Lfu", func(capacity int) ILru[int, int] { return NewLfu[int, int](capacity, nil) }},
+	{"Arc", func(capacity int) ILru[int, int] { return NewArc[int, int](capacity, nil) }},
+	{"TwoQueue", func(capacity int) ILru[int, int] { return NewTwoQueue[int, int](capacity, 0, 0, nil) }},
+	{"Slru", func(capacity int) ILru[int, int] { return NewSlru[int, int](capacity, 0, nil) }},
+	{"TinyLfu", func(capacity int) ILru[int, int] { return NewTinyLfu[int, int](capacity, nil, nil) }},
+	{"Sieve", func(capacity int) ILru[int, int] { return NewSieve[int, int](capacity, nil) }},
+	{"ArenaLru", func(capacity int) ILru[int, int] { return NewArenaLru[int, int](capacity, nil) }},
+	{"LruK", func(capacity int) ILru[int, int] { return NewLruK[int, int](capacity, 2, nil) }},
+}
+
+const (
+	traceLength   = 1 << 16
+	traceCapacity = 1000
+)
+
+// zipfTrace draws keys following a zipf distribution over keyCount keys
+func zipfTrace(seed int64, keyCount uint64, length int) []int {
+	random := rand.New(rand.NewSource(seed))
+	zipf := rand.NewZipf(random, 1.1, 1, keyCount-1)
+	trace := make([]int, length)
+	for i := range trace {
+		trace[i] = int(zipf.Uint64())
+	}
+	return trace
+}
+
+// scanTrace interleaves zipf accesses to a hot set with sequential scans of
+// cold keys as long as the cache
+func scanTrace(seed int64, length int) []int {
+	hot := zipfTrace(seed, traceCapacity*2, length)
+	trace := make([]int, 0, length)
+	cold := 1 << 20
+	for len(trace) < length {
+		for j := 0; j < traceCapacity*3 && len(trace) < length; j++ {
+			trace = append(trace, hot[len(trace)])
+		}
+		for j := 0; j < traceCapacity && len(trace) < length; j++ {
+			trace = append(trace, cold)
+			cold++
+		}
+	}
+	return trace
+}
+
+// hitRatio replays trace as a read-through cache
+func hitRatio(cache ILru[int, int], trace []int) float64 {
+	hits := 0
+	for _, key := range trace {
+		if _, ok := cache.Get(key); ok {
+			hits++
+		} else {
+			cache.Add(key, key)
+		}
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+func benchmarkHitRatio(b *testing.B, trace []int) {
+	for _, policy := range policies {
+		b.Run(policy.name, func(b *testing.B) {
+			cache := policy.new(traceCapacity)
+			hits := 0
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := trace[i%len(trace)]
+				if _, ok := cache.Get(key); ok {
+					hits++
+				} else {
+					cache.Add(key, key)
+				}
+			}
+			b.ReportMetric(float64(hits)*100/float64(b.N), "hit%")
+		})
+	}
+}
+
+func BenchmarkHitRatioZipf(b *testing.B) {
+	benchmarkHitRatio(b, zipfTrace(1, traceCapacity*100, traceLength))
+}
+
+func BenchmarkHitRatioScan(b *testing.B) {
+	benchmarkHitRatio(b, scanTrace(1, traceLength))
+}
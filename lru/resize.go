@@ -0,0 +1,109 @@
+package lru
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+// Resize changes the max entry count and evicts from the tail right away
+// when shrinking, with EvictReasonCapacity. capacity <= 0 means unbounded.
+// It returns the number of evicted entries.
+func (lru *Lru[k, v]) Resize(capacity int) (evicted int) {
+	if capacity < 0 {
+		capacity = 0
+	}
+	lru.capacity = capacity
+	return lru.trim()
+}
+
+func (cache *SyncLru[k, v]) Resize(capacity int) (evicted int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Resize(capacity)
+}
+
+// Resize spreads the new capacity over the shards like NewShardedLru does,
+// every shard keeps a capacity of at least 1 while the cache is bounded
+func (cache *ShardedLru[k, v]) Resize(capacity int) (evicted int) {
+	shardCount := len(cache.shards)
+	for i, shard := range cache.shards {
+		shardCapacity := 0
+		if capacity > 0 {
+			shardCapacity = capacity / shardCount
+			if i < capacity%shardCount {
+				shardCapacity++
+			}
+			if shardCapacity < 1 {
+				shardCapacity = 1
+			}
+		}
+		evicted += shard.Resize(shardCapacity)
+	}
+	return evicted
+}
+
+// Resize changes the cost budget and evicts from the tail until it fits,
+// capacity <= 0 means unbounded
+func (lru *CostLru[k, v]) Resize(capacity int64) (evicted int) {
+	if capacity < 0 {
+		capacity = 0
+	}
+	lru.capacity = capacity
+	return lru.trim()
+}
+
+// resizer is implemented by the thread-safe caches WatchMemory can shrink
+type resizer interface {
+	Len() int
+	Cap() int
+	Resize(capacity int) (evicted int)
+}
+
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// heapBytes reads the bytes held by live and not yet swept heap objects,
+// replaced in tests
+var heapBytes = func() uint64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// watchMemory shrinks cache by shrinkRatio of its size every interval while
+// the heap is above limit, never below one entry
+func watchMemory(cache resizer, limit uint64, interval time.Duration, shrinkRatio float64) (stop func()) {
+	if shrinkRatio <= 0 || shrinkRatio >= 1 {
+		shrinkRatio = 0.1
+	}
+	return every(interval, func() {
+		if heapBytes() <= limit {
+			return
+		}
+		size := cache.Cap()
+		if length := cache.Len(); size == 0 || length < size {
+			size = length
+		}
+		capacity := int(float64(size) * (1 - shrinkRatio))
+		if capacity < 1 {
+			capacity = 1
+		}
+		cache.Resize(capacity)
+	})
+}
+
+// WatchMemory checks the go heap every interval and, while it is above the
+// soft limit in bytes, shrinks the capacity by shrinkRatio of the current
+// size (0.1 if outside (0, 1)), evicting from the tail. The capacity is not
+// grown back, call Resize once the pressure is gone. Stop watching with the
+// returned function, interval <= 0 starts nothing.
+func (cache *SyncLru[k, v]) WatchMemory(limit uint64, interval time.Duration, shrinkRatio float64) (stop func()) {
+	return watchMemory(cache, limit, interval, shrinkRatio)
+}
+
+// WatchMemory works as SyncLru.WatchMemory over the whole sharded cache
+func (cache *ShardedLru[k, v]) WatchMemory(limit uint64, interval time.Duration, shrinkRatio float64) (stop func()) {
+	return watchMemory(cache, limit, interval, shrinkRatio)
+}
@@ -0,0 +1,102 @@
+package lru
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ResizeTestSuite struct {
+	suite.Suite
+}
+
+func (s *ResizeTestSuite) TestResize() {
+	var evicted []int
+	lru := NewLruWithCapacity(4, func(key int, value int, reason EvictReason) {
+		s.Equal(EvictReasonCapacity, reason)
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < 4; i++ {
+		lru.Add(i, i)
+	}
+	lru.Get(0)
+
+	s.Equal(2, lru.Resize(2))
+	s.Equal([]int{1, 2}, evicted)
+	s.Equal(2, lru.Len())
+	s.Equal(2, lru.Cap())
+
+	s.Equal(0, lru.Resize(0))
+	for i := 10; i < 20; i++ {
+		lru.Add(i, i)
+	}
+	s.Equal(12, lru.Len())
+	s.Equal(uint64(2), lru.Stats().Evictions[EvictReasonCapacity])
+}
+
+func (s *ResizeTestSuite) TestResizeSharded() {
+	cache := NewShardedLru[int, int](4, 100, nil, nil)
+	for i := 0; i < 100; i++ {
+		cache.Add(i, i)
+	}
+	before := cache.Len()
+	evicted := cache.Resize(10)
+	s.Equal(10, cache.Cap())
+	s.LessOrEqual(cache.Len(), 10)
+	s.Equal(before-cache.Len(), evicted)
+
+	cache.Resize(2)
+	s.Equal(4, cache.Cap())
+}
+
+func (s *ResizeTestSuite) TestResizeCost() {
+	lru := NewCostLru[int, int](10, nil, nil)
+	for i := 0; i < 10; i++ {
+		lru.Add(i, i)
+	}
+	s.Equal(5, lru.Resize(5))
+	s.Equal(int64(5), lru.CurrentCost())
+}
+
+func (s *ResizeTestSuite) TestWatchMemory() {
+	var heap uint64 = 100
+	saved := heapBytes
+	heapBytes = func() uint64 {
+		return atomic.LoadUint64(&heap)
+	}
+	defer func() {
+		heapBytes = saved
+	}()
+
+	cache := NewSyncLru[int, int](0, nil)
+	for i := 0; i < 100; i++ {
+		cache.Add(i, i)
+	}
+	stop := cache.WatchMemory(200, time.Millisecond, 0.5)
+	time.Sleep(10 * time.Millisecond)
+	s.Equal(100, cache.Len())
+	s.Equal(0, cache.Cap())
+
+	atomic.StoreUint64(&heap, 300)
+	s.Eventually(func() bool {
+		return cache.Len() <= 25
+	}, time.Second, time.Millisecond)
+	atomic.StoreUint64(&heap, 100)
+	stop()
+
+	length := cache.Len()
+	s.GreaterOrEqual(length, 1)
+	s.Equal(length, cache.Cap())
+	_, ok := cache.Peek(99)
+	s.True(ok)
+}
+
+func (s *ResizeTestSuite) TestHeapBytes() {
+	s.Greater(heapBytes(), uint64(0))
+}
+
+func TestResizeTestSuite(t *testing.T) {
+	suite.Run(t, new(ResizeTestSuite))
+}
@@ -0,0 +1,233 @@
+package lru
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+)
+
+// Hasher maps a key to a 64 bits hash used to pick its shard
+type Hasher[k comparable] func(key k) uint64
+
+var hashSeed = maphash.MakeSeed()
+
+// DefaultHasher hashes strings, integers and floats directly, other
+// comparable types are hashed through their fmt representation which is
+// slow and gives different hashes to equal keys holding -0 and +0 floats,
+// pass a dedicated Hasher for struct or pointer keys.
+func DefaultHasher[k comparable](key k) uint64 {
+	switch key := any(key).(type) {
+	case string:
+		return hashString(key)
+	case int:
+		return mix64(uint64(key))
+	case int8:
+		return mix64(uint64(key))
+	case int16:
+		return mix64(uint64(key))
+	case int32:
+		return mix64(uint64(key))
+	case int64:
+		return mix64(uint64(key))
+	case uint:
+		return mix64(uint64(key))
+	case uint8:
+		return mix64(uint64(key))
+	case uint16:
+		return mix64(uint64(key))
+	case uint32:
+		return mix64(uint64(key))
+	case uint64:
+		return mix64(key)
+	case uintptr:
+		return mix64(uint64(key))
+	case float32:
+		return hashFloat(float64(key))
+	case float64:
+		return hashFloat(key)
+	}
+	return hashString(fmt.Sprintf("%#v", key))
+}
+
+func hashString(key string) uint64 {
+	var hash maphash.Hash
+	hash.SetSeed(hashSeed)
+	hash.WriteString(key)
+	return hash.Sum64()
+}
+
+// hashFloat hashes -0 as 0 since they compare equal
+func hashFloat(key float64) uint64 {
+	if key == 0 {
+		key = 0
+	}
+	return mix64(math.Float64bits(key))
+}
+
+// mix64 is the splitmix64 finalizer, spreads sequential integers over shards
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// ShardedLru spreads keys over independent SyncLru shards to cut lock
+// contention. Recency is tracked per shard, so eviction, RemoveOldest and
+// the iterate order are only lru within a shard.
+type ShardedLru[k comparable, v any] struct {
+	shards []*SyncLru[k, v]
+	hasher Hasher[k]
+}
+
+// ShardStat describes a single shard
+type ShardStat struct {
+	Len int
+	Cap int
+}
+
+var _ ILru[int, int] = (*ShardedLru[int, int])(nil)
+
+// NewShardedLru splits capacity over shardCount shards, capacity <= 0 means
+// unbounded. A bounded cache never gets more shards than its capacity.
+// hasher defaults to DefaultHasher when nil.
+func NewShardedLru[k comparable, v any](shardCount, capacity int, hasher Hasher[k], onEvict EvictFunc[k, v]) *ShardedLru[k, v] {
+	if shardCount <= 0 {
+		shardCount = 1
+	}
+	if capacity > 0 && shardCount > capacity {
+		shardCount = capacity
+	}
+	if hasher == nil {
+		hasher = DefaultHasher[k]
+	}
+	cache := &ShardedLru[k, v]{
+		shards: make([]*SyncLru[k, v], shardCount),
+		hasher: hasher,
+	}
+	for i := range cache.shards {
+		shardCapacity := 0
+		if capacity > 0 {
+			shardCapacity = capacity / shardCount
+			if i < capacity%shardCount {
+				shardCapacity++
+			}
+		}
+		cache.shards[i] = NewSyncLru(shardCapacity, onEvict)
+	}
+	return cache
+}
+
+func (cache *ShardedLru[k, v]) shard(key k) *SyncLru[k, v] {
+	return cache.shards[cache.shardIndex(key)]
+}
+
+func (cache *ShardedLru[k, v]) shardIndex(key k) int {
+	return int(cache.hasher(key) % uint64(len(cache.shards)))
+}
+
+func (cache *ShardedLru[k, v]) Add(key k, value v) (overwrite bool) {
+	return cache.shard(key).Add(key, value)
+}
+
+func (cache *ShardedLru[k, v]) Get(key k) (value v, exist bool) {
+	return cache.shard(key).Get(key)
+}
+
+func (cache *ShardedLru[k, v]) Peek(key k) (value v, exist bool) {
+	return cache.shard(key).Peek(key)
+}
+
+func (cache *ShardedLru[k, v]) Contains(key k) bool {
+	return cache.shard(key).Contains(key)
+}
+
+func (cache *ShardedLru[k, v]) GetOrAdd(key k, value v) (actual v, loaded bool) {
+	return cache.shard(key).GetOrAdd(key, value)
+}
+
+func (cache *ShardedLru[k, v]) ContainsOrAdd(key k, value v) (exist bool) {
+	return cache.shard(key).ContainsOrAdd(key, value)
+}
+
+func (cache *ShardedLru[k, v]) Remove(key k) (exist bool) {
+	return cache.shard(key).Remove(key)
+}
+
+// RemoveOldest pops the oldest entry of the first shard having one to pop,
+// shards holding only pinned or expired entries are skipped
+func (cache *ShardedLru[k, v]) RemoveOldest() (key k, value v) {
+	for _, shard := range cache.shards {
+		shard.lock.Lock()
+		key, value, removed := shard.lru.removeOldest()
+		shard.lock.Unlock()
+		if removed {
+			return key, value
+		}
+	}
+	return
+}
+
+func (cache *ShardedLru[k, v]) Clear() {
+	for _, shard := range cache.shards {
+		shard.Clear()
+	}
+}
+
+func (cache *ShardedLru[k, v]) Len() int {
+	length := 0
+	for _, shard := range cache.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+func (cache *ShardedLru[k, v]) Cap() int {
+	capacity := 0
+	for _, shard := range cache.shards {
+		capacity += shard.Cap()
+	}
+	return capacity
+}
+
+// Iterate walks the shards one after another, each shard is locked only
+// while it is walked so the result is not a consistent snapshot
+func (cache *ShardedLru[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	cache.iterate(iterateFunc, false)
+}
+
+func (cache *ShardedLru[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	cache.iterate(iterateFunc, true)
+}
+
+func (cache *ShardedLru[k, v]) iterate(iterateFunc IterateFunc[k, v], reverse bool) {
+	stop := false
+	wrapper := func(key k, value v) bool {
+		stop = iterateFunc(key, value)
+		return stop
+	}
+	for _, shard := range cache.shards {
+		if reverse {
+			shard.IterateList(wrapper)
+		} else {
+			shard.Iterate(wrapper)
+		}
+		if stop {
+			return
+		}
+	}
+}
+
+// ShardStats returns the length and capacity of every shard
+func (cache *ShardedLru[k, v]) ShardStats() []ShardStat {
+	stats := make([]ShardStat, len(cache.shards))
+	for i, shard := range cache.shards {
+		stats[i] = ShardStat{
+			Len: shard.Len(),
+			Cap: shard.Cap(),
+		}
+	}
+	return stats
+}
@@ -0,0 +1,106 @@
+package lru
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ShardedLruTestSuite struct {
+	suite.Suite
+}
+
+func (s *ShardedLruTestSuite) TestCapacitySplit() {
+	cache := NewShardedLru[string, int](4, 10, nil, nil)
+	stats := cache.ShardStats()
+	s.Equal(4, len(stats))
+	s.Equal(10, cache.Cap())
+	s.Equal([]int{3, 3, 2, 2}, []int{stats[0].Cap, stats[1].Cap, stats[2].Cap, stats[3].Cap})
+
+	cache = NewShardedLru[string, int](16, 3, nil, nil)
+	s.Equal(3, len(cache.ShardStats()))
+	s.Equal(3, cache.Cap())
+}
+
+func (s *ShardedLruTestSuite) TestAggregate() {
+	cache := NewShardedLru[string, int](4, 0, nil, nil)
+	for i := 0; i < 100; i++ {
+		cache.Add(fmt.Sprintf("key-%v", i), i)
+	}
+	s.Equal(100, cache.Len())
+
+	length := 0
+	for _, stat := range cache.ShardStats() {
+		s.Greater(stat.Len, 0)
+		length += stat.Len
+	}
+	s.Equal(100, length)
+
+	sum := 0
+	cache.Iterate(func(key string, value int) bool {
+		sum += value
+		return false
+	})
+	s.Equal(99*100/2, sum)
+}
+
+func (s *ShardedLruTestSuite) TestHasher() {
+	cache := NewShardedLru[int, int](4, 0, func(key int) uint64 {
+		return 0
+	}, nil)
+	for i := 0; i < 10; i++ {
+		cache.Add(i, i)
+	}
+	stats := cache.ShardStats()
+	s.Equal(10, stats[0].Len)
+	s.Equal(0, stats[3].Len)
+
+	type point struct{ x, y int }
+	s.Equal(DefaultHasher(point{1, 2}), DefaultHasher(point{1, 2}))
+	s.NotEqual(DefaultHasher(point{1, 2}), DefaultHasher(point{2, 1}))
+
+	// -0 and +0 are equal keys
+	negativeZero := math.Copysign(0, -1)
+	s.Equal(DefaultHasher(0.0), DefaultHasher(negativeZero))
+	s.Equal(DefaultHasher(float32(0)), DefaultHasher(float32(negativeZero)))
+	floats := NewShardedLru[float64, int](16, 0, nil, nil)
+	floats.Add(negativeZero, 1)
+	value, ok := floats.Get(0)
+	s.True(ok)
+	s.Equal(1, value)
+}
+
+func TestShardedLruTestSuite(t *testing.T) {
+	suite.Run(t, new(ShardedLruTestSuite))
+}
+
+const benchmarkKeys = 1 << 16
+
+func benchmarkParallel(b *testing.B, cache ILru[int, int]) {
+	for i := 0; i < benchmarkKeys/2; i++ {
+		cache.Add(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := int(mix64(uint64(i)) % benchmarkKeys)
+			if i%4 == 0 {
+				cache.Add(key, i)
+			} else {
+				cache.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncLruParallel(b *testing.B) {
+	benchmarkParallel(b, NewSyncLru[int, int](benchmarkKeys/2, nil))
+}
+
+func BenchmarkShardedLruParallel(b *testing.B) {
+	benchmarkParallel(b, NewShardedLru[int, int](64, benchmarkKeys/2, nil, nil))
+}
@@ -0,0 +1,206 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type sieveEntry[v any] struct {
+	value   v
+	visited int32
+}
+
+// Sieve implements a thread-safe SIEVE cache. A hit only sets the visited
+// bit of the entry atomically, so Get runs under the read lock and readers
+// never contend with each other. On insertion into a full cache the hand
+// walks from the tail toward the head, clearing visited bits until it meets
+// an unvisited entry to evict. onEvict runs with the write lock held.
+type Sieve[k comparable, v any] struct {
+	lock     sync.RWMutex
+	list     *List[k, sieveEntry[v]]
+	hash     map[k]*Node[k, sieveEntry[v]]
+	hand     *Node[k, sieveEntry[v]]
+	capacity int
+	onEvict  EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*Sieve[int, int])(nil)
+
+// NewSieve returns a sieve holding at most capacity entries, capacity is at
+// least 1
+func NewSieve[k comparable, v any](capacity int, onEvict EvictFunc[k, v]) *Sieve[k, v] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Sieve[k, v]{
+		list:     NewList[k, sieveEntry[v]](),
+		hash:     make(map[k]*Node[k, sieveEntry[v]]),
+		capacity: capacity,
+		onEvict:  onEvict,
+	}
+}
+
+// Add inserts a new entry at the head, or replaces the value of an existing
+// one and marks it visited
+func (sieve *Sieve[k, v]) Add(key k, value v) (overwrite bool) {
+	sieve.lock.Lock()
+	defer sieve.lock.Unlock()
+	if node, ok := sieve.hash[key]; ok {
+		node.value.value = value
+		atomic.StoreInt32(&node.value.visited, 1)
+		return true
+	}
+	if sieve.list.Len() >= sieve.capacity {
+		sieve.removeNode(sieve.victim(), EvictReasonCapacity)
+	}
+	sieve.hash[key] = sieve.list.Prepend(key, sieveEntry[v]{value: value})
+	return false
+}
+
+// Get only takes the read lock
+func (sieve *Sieve[k, v]) Get(key k) (value v, exist bool) {
+	sieve.lock.RLock()
+	defer sieve.lock.RUnlock()
+	if node, ok := sieve.hash[key]; ok {
+		if atomic.LoadInt32(&node.value.visited) == 0 {
+			atomic.StoreInt32(&node.value.visited, 1)
+		}
+		return node.value.value, true
+	}
+	return value, false
+}
+
+// Peek returns the value of key without marking it visited
+func (sieve *Sieve[k, v]) Peek(key k) (value v, exist bool) {
+	sieve.lock.RLock()
+	defer sieve.lock.RUnlock()
+	if node, ok := sieve.hash[key]; ok {
+		return node.value.value, true
+	}
+	return value, false
+}
+
+func (sieve *Sieve[k, v]) Contains(key k) bool {
+	sieve.lock.RLock()
+	defer sieve.lock.RUnlock()
+	_, ok := sieve.hash[key]
+	return ok
+}
+
+func (sieve *Sieve[k, v]) Remove(key k) (exist bool) {
+	sieve.lock.Lock()
+	defer sieve.lock.Unlock()
+	if node, ok := sieve.hash[key]; ok {
+		sieve.removeNode(node, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest moves the hand like an eviction would and pops its victim
+func (sieve *Sieve[k, v]) RemoveOldest() (key k, value v) {
+	sieve.lock.Lock()
+	defer sieve.lock.Unlock()
+	node := sieve.victim()
+	if node == nil {
+		return
+	}
+	sieve.removeNode(node, EvictReasonRemoved)
+	return node.key, node.value.value
+}
+
+func (sieve *Sieve[k, v]) Clear() {
+	sieve.lock.Lock()
+	defer sieve.lock.Unlock()
+	list := sieve.list
+	sieve.list = NewList[k, sieveEntry[v]]()
+	sieve.hash = make(map[k]*Node[k, sieveEntry[v]])
+	sieve.hand = nil
+	if sieve.onEvict != nil {
+		list.Iterate(func(key k, entry sieveEntry[v]) bool {
+			sieve.onEvict(key, entry.value, EvictReasonCleared)
+			return false
+		})
+	}
+}
+
+func (sieve *Sieve[k, v]) Len() int {
+	sieve.lock.RLock()
+	defer sieve.lock.RUnlock()
+	return sieve.list.Len()
+}
+
+func (sieve *Sieve[k, v]) Cap() int {
+	return sieve.capacity
+}
+
+// Iterate walks from the most recently inserted entry
+func (sieve *Sieve[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	sieve.lock.RLock()
+	defer sieve.lock.RUnlock()
+	sieve.list.Iterate(func(key k, entry sieveEntry[v]) bool {
+		return iterateFunc(key, entry.value)
+	})
+}
+
+// IterateList walks in the order entries would be evicted if no further
+// access happened: the unvisited entries from the hand to the head, then
+// the rest from the tail
+func (sieve *Sieve[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	sieve.lock.RLock()
+	defer sieve.lock.RUnlock()
+	start := sieve.start()
+	stop := false
+	first := make(map[*Node[k, sieveEntry[v]]]bool)
+	for node := start; node != nil && node != sieve.list.head; node = node.pre {
+		if atomic.LoadInt32(&node.value.visited) == 0 {
+			first[node] = true
+			if iterateFunc(node.key, node.value.value) {
+				return
+			}
+		}
+	}
+	sieve.list.walk(true, func(node *Node[k, sieveEntry[v]]) bool {
+		if !first[node] {
+			stop = iterateFunc(node.key, node.value.value)
+		}
+		return stop
+	})
+}
+
+// start returns the node the hand points at, the tail if it wrapped
+func (sieve *Sieve[k, v]) start() *Node[k, sieveEntry[v]] {
+	if sieve.hand == nil || sieve.hand == sieve.list.head {
+		return sieve.list.Back()
+	}
+	return sieve.hand
+}
+
+// victim moves the hand to the next unvisited entry, clearing visited bits
+// on the way. Removing the victim then moves the hand one step further.
+func (sieve *Sieve[k, v]) victim() *Node[k, sieveEntry[v]] {
+	if sieve.list.Len() == 0 {
+		return nil
+	}
+	node := sieve.start()
+	for atomic.LoadInt32(&node.value.visited) != 0 {
+		atomic.StoreInt32(&node.value.visited, 0)
+		node = node.pre
+		if node == sieve.list.head {
+			node = sieve.list.Back()
+		}
+	}
+	sieve.hand = node
+	return node
+}
+
+func (sieve *Sieve[k, v]) removeNode(node *Node[k, sieveEntry[v]], reason EvictReason) {
+	if sieve.hand == node {
+		sieve.hand = node.pre
+	}
+	sieve.list.Remove(node)
+	delete(sieve.hash, node.key)
+	if sieve.onEvict != nil {
+		sieve.onEvict(node.key, node.value.value, reason)
+	}
+}
@@ -0,0 +1,105 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SieveTestSuite struct {
+	suite.Suite
+	sieve *Sieve[int, int]
+}
+
+func (s *SieveTestSuite) SetupTest() {
+	s.sieve = NewSieve[int, int](3, nil)
+}
+
+func (s *SieveTestSuite) keys() []int {
+	var keys []int
+	s.sieve.Iterate(func(key int, value int) bool {
+		keys = append(keys, key)
+		return false
+	})
+	return keys
+}
+
+func (s *SieveTestSuite) TestEvict() {
+	s.sieve.Add(1, 1)
+	s.sieve.Add(2, 2)
+	s.sieve.Add(3, 3)
+	s.sieve.Get(1)
+
+	// hand skips visited 1 and evicts 2
+	s.sieve.Add(4, 4)
+	s.Equal([]int{4, 3, 1}, s.keys())
+
+	// hand keeps moving toward the head
+	s.sieve.Add(5, 5)
+	s.Equal([]int{5, 4, 1}, s.keys())
+	s.sieve.Add(6, 6)
+	s.Equal([]int{6, 5, 1}, s.keys())
+	s.sieve.Add(7, 7)
+	s.Equal([]int{7, 6, 1}, s.keys())
+
+	// hand clears 6 and 7 then wraps to the tail, 1 was cleared on the
+	// first pass
+	s.sieve.Get(6)
+	s.sieve.Get(7)
+	s.sieve.Add(8, 8)
+	s.Equal([]int{8, 7, 6}, s.keys())
+}
+
+func (s *SieveTestSuite) TestIterateList() {
+	for i := 1; i <= 3; i++ {
+		s.sieve.Add(i, i)
+	}
+	s.sieve.Get(1)
+	s.sieve.Get(3)
+
+	var keys []int
+	s.sieve.IterateList(func(key int, value int) bool {
+		keys = append(keys, key)
+		return false
+	})
+	s.Equal([]int{2, 1, 3}, keys)
+
+	key, _ := s.sieve.RemoveOldest()
+	s.Equal(2, key)
+	key, _ = s.sieve.RemoveOldest()
+	s.Equal(1, key)
+}
+
+func TestSieveTestSuite(t *testing.T) {
+	suite.Run(t, new(SieveTestSuite))
+}
+
+func benchmarkParallelGet(b *testing.B, cache ILru[int, int]) {
+	for i := 0; i < benchmarkKeys; i++ {
+		cache.Add(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get(int(mix64(uint64(i)) % benchmarkKeys))
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncLruParallelGet(b *testing.B) {
+	benchmarkParallelGet(b, NewSyncLru[int, int](benchmarkKeys, nil))
+}
+
+func BenchmarkShardedLruParallelGet(b *testing.B) {
+	benchmarkParallelGet(b, NewShardedLru[int, int](64, benchmarkKeys, nil, nil))
+}
+
+func BenchmarkSieveParallelGet(b *testing.B) {
+	benchmarkParallelGet(b, NewSieve[int, int](benchmarkKeys, nil))
+}
+
+func BenchmarkSieveParallel(b *testing.B) {
+	benchmarkParallel(b, NewSieve[int, int](benchmarkKeys/2, nil))
+}
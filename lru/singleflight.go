@@ -0,0 +1,86 @@
+package lru
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// PanicError is returned to the callers of a load that panicked, the panic
+// is recovered so it does not kill the process from the load goroutine
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("lru: load panicked: %v\n\n%s", err.Value, err.Stack)
+}
+
+type call[v any] struct {
+	done  chan struct{}
+	value v
+	err   error
+}
+
+// group deduplicates concurrent calls sharing the same key
+type group[k comparable, v any] struct {
+	lock  sync.Mutex
+	calls map[k]*call[v]
+}
+
+// do runs fn once for all concurrent callers of key and shares its result.
+// fn runs in its own goroutine so a caller whose ctx is done stops waiting
+// without cancelling the call for the others. A panic of fn is returned to
+// every caller as a *PanicError.
+func (g *group[k, v]) do(ctx context.Context, key k, fn func() (v, error)) (value v, err error) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[k]*call[v])
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		c = &call[v]{done: make(chan struct{})}
+		g.calls[key] = c
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					c.err = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+				g.lock.Lock()
+				delete(g.calls, key)
+				g.lock.Unlock()
+				close(c.done)
+			}()
+			c.value, c.err = fn()
+		}()
+	}
+	g.lock.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		return value, ctx.Err()
+	}
+}
+
+// detachedContext keeps the values of its parent but is never cancelled,
+// a shared load must not fail because the caller that started it left
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package lru
+
+const (
+	sketchDepth      = 4
+	sketchMaxCounter = 15
+	// sketchSampleRatio times the capacity additions trigger an aging
+	sketchSampleRatio = 10
+)
+
+// countMinSketch estimates access frequencies in constant space. Counters
+// saturate at 15 and are halved every sampleSize additions, so old
+// popularity fades away.
+type countMinSketch struct {
+	counters   []uint8
+	mask       uint64
+	additions  int
+	sampleSize int
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := 16
+	for width < capacity {
+		width <<= 1
+	}
+	return &countMinSketch{
+		counters:   make([]uint8, sketchDepth*width),
+		mask:       uint64(width - 1),
+		sampleSize: sketchSampleRatio * capacity,
+	}
+}
+
+// index returns the counter of row i for hash, rows use double hashing
+func (sketch *countMinSketch) index(hash uint64, i int) int {
+	h2 := mix64(hash) | 1
+	return i*int(sketch.mask+1) + int((hash+uint64(i)*h2)&sketch.mask)
+}
+
+func (sketch *countMinSketch) Increment(hash uint64) {
+	added := false
+	for i := 0; i < sketchDepth; i++ {
+		index := sketch.index(hash, i)
+		if sketch.counters[index] < sketchMaxCounter {
+			sketch.counters[index]++
+			added = true
+		}
+	}
+	if added {
+		sketch.additions++
+		if sketch.additions >= sketch.sampleSize {
+			sketch.age()
+		}
+	}
+}
+
+func (sketch *countMinSketch) Estimate(hash uint64) uint8 {
+	estimate := uint8(sketchMaxCounter)
+	for i := 0; i < sketchDepth; i++ {
+		if counter := sketch.counters[sketch.index(hash, i)]; counter < estimate {
+			estimate = counter
+		}
+	}
+	return estimate
+}
+
+func (sketch *countMinSketch) age() {
+	for i := range sketch.counters {
+		sketch.counters[i] >>= 1
+	}
+	sketch.additions /= 2
+}
+
+func (sketch *countMinSketch) Reset() {
+	for i := range sketch.counters {
+		sketch.counters[i] = 0
+	}
+	sketch.additions = 0
+}
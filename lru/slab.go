@@ -0,0 +1,245 @@
+package lru
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// ErrEntryTooLarge is returned when an entry does not fit in a SlabCache
+// shard
+var ErrEntryTooLarge = errors.New("lru: entry exceeds slab shard size")
+
+// slabHeaderSize is the size of the entry header: key hash, key length and
+// value length
+const slabHeaderSize = 16
+
+// SlabCache caches []byte values in large preallocated byte rings, one per
+// shard, indexed by map[uint64]uint64 so the gc has no per entry pointer to
+// scan. Entries are appended at the ring head and evicted from the tail
+// when a write wraps around onto them. Get re-appends entries found in the
+// older half of the ring, which keeps the eviction order approximately lru.
+// Keys are stored with the values and compared on lookup, a key whose hash
+// collides with a cached key replaces it.
+type SlabCache struct {
+	shards []*slabShard
+}
+
+type slabShard struct {
+	lock sync.Mutex
+	ring []byte
+	// head and tail are the absolute offsets of the next write and of the
+	// oldest entry, an offset is at ring position offset % len(ring)
+	head, tail uint64
+	// index maps key hashes to entry offsets
+	index map[uint64]uint64
+	stats statsCounter
+}
+
+// NewSlabCache returns a cache holding at most capacity bytes of entries
+// split into shardCount rings, every entry takes its key and value length
+// plus a 16 bytes header.
+func NewSlabCache(shardCount int, capacity int) *SlabCache {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	shardSize := capacity / shardCount
+	if shardSize < slabHeaderSize {
+		shardSize = slabHeaderSize
+	}
+	cache := &SlabCache{shards: make([]*slabShard, shardCount)}
+	for i := range cache.shards {
+		cache.shards[i] = &slabShard{
+			ring:  make([]byte, shardSize),
+			index: make(map[uint64]uint64),
+		}
+	}
+	return cache
+}
+
+func (cache *SlabCache) shard(key string) (*slabShard, uint64) {
+	hash := hashString(key)
+	return cache.shards[hash%uint64(len(cache.shards))], hash
+}
+
+// Set stores a copy of value, ErrEntryTooLarge is returned if the entry
+// does not fit in a shard and a previous value of key is removed
+func (cache *SlabCache) Set(key string, value []byte) error {
+	shard, hash := cache.shard(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	return shard.set(hash, key, value)
+}
+
+// Get returns a copy of the value of key
+func (cache *SlabCache) Get(key string) (value []byte, exist bool) {
+	shard, hash := cache.shard(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	return shard.get(hash, key)
+}
+
+func (cache *SlabCache) Remove(key string) (exist bool) {
+	shard, hash := cache.shard(key)
+	shard.lock.Lock()
+	defer shard.lock.Unlock()
+	offset, ok := shard.index[hash]
+	if !ok || !shard.match(offset, key) {
+		return false
+	}
+	delete(shard.index, hash)
+	shard.stats.evict(EvictReasonRemoved, 1)
+	return true
+}
+
+func (cache *SlabCache) Len() int {
+	length := 0
+	for _, shard := range cache.shards {
+		shard.lock.Lock()
+		length += len(shard.index)
+		shard.lock.Unlock()
+	}
+	return length
+}
+
+// Clear drops every entry, the rings are kept
+func (cache *SlabCache) Clear() {
+	for _, shard := range cache.shards {
+		shard.lock.Lock()
+		shard.stats.evict(EvictReasonCleared, len(shard.index))
+		shard.index = make(map[uint64]uint64)
+		shard.head, shard.tail = 0, 0
+		shard.lock.Unlock()
+	}
+}
+
+func (cache *SlabCache) Stats() Stats {
+	var stats Stats
+	for _, shard := range cache.shards {
+		shardStats := shard.stats.snapshot()
+		shard.lock.Lock()
+		shardStats.Size = len(shard.index)
+		shard.lock.Unlock()
+		stats.add(shardStats)
+	}
+	return stats
+}
+
+func (cache *SlabCache) ResetStats() {
+	for _, shard := range cache.shards {
+		shard.stats.reset()
+	}
+}
+
+func (shard *slabShard) set(hash uint64, key string, value []byte) error {
+	offset, ok := shard.index[hash]
+	update := ok && shard.match(offset, key)
+	if slabHeaderSize+len(key)+len(value) > len(shard.ring) {
+		if ok {
+			delete(shard.index, hash)
+			shard.stats.evict(EvictReasonCapacity, 1)
+		}
+		return ErrEntryTooLarge
+	}
+	if update {
+		shard.stats.update()
+	} else {
+		if ok {
+			// the colliding key is replaced
+			shard.stats.evict(EvictReasonCapacity, 1)
+		}
+		shard.stats.insert()
+	}
+	shard.append(hash, key, value)
+	return nil
+}
+
+func (shard *slabShard) get(hash uint64, key string) (value []byte, exist bool) {
+	offset, ok := shard.index[hash]
+	if !ok || !shard.match(offset, key) {
+		shard.stats.miss()
+		return nil, false
+	}
+	shard.stats.hit()
+	_, valueLen := shard.header(offset)
+	value = make([]byte, valueLen)
+	shard.read(offset+slabHeaderSize+uint64(len(key)), value)
+	if shard.head-offset > uint64(len(shard.ring))/2 {
+		// move it away from the tail before the ring wraps onto it
+		shard.append(hash, key, value)
+	}
+	return value, true
+}
+
+// append writes the entry at the head, evicting from the tail what it
+// overwrites, and indexes it
+func (shard *slabShard) append(hash uint64, key string, value []byte) {
+	// a previous entry of hash is garbage now, eviction must not report it
+	delete(shard.index, hash)
+	size := uint64(slabHeaderSize + len(key) + len(value))
+	for shard.head+size-shard.tail > uint64(len(shard.ring)) {
+		shard.evictTail()
+	}
+	var header [slabHeaderSize]byte
+	binary.LittleEndian.PutUint64(header[0:], hash)
+	binary.LittleEndian.PutUint32(header[8:], uint32(len(key)))
+	binary.LittleEndian.PutUint32(header[12:], uint32(len(value)))
+	offset := shard.head
+	shard.write(offset, header[:])
+	first, second := shard.slice(offset+slabHeaderSize, len(key))
+	copy(second, key[copy(first, key):])
+	shard.write(offset+slabHeaderSize+uint64(len(key)), value)
+	shard.head += size
+	shard.index[hash] = offset
+}
+
+func (shard *slabShard) evictTail() {
+	var header [slabHeaderSize]byte
+	shard.read(shard.tail, header[:])
+	hash := binary.LittleEndian.Uint64(header[0:])
+	size := uint64(slabHeaderSize) +
+		uint64(binary.LittleEndian.Uint32(header[8:])) +
+		uint64(binary.LittleEndian.Uint32(header[12:]))
+	if offset, ok := shard.index[hash]; ok && offset == shard.tail {
+		delete(shard.index, hash)
+		shard.stats.evict(EvictReasonCapacity, 1)
+	}
+	shard.tail += size
+}
+
+func (shard *slabShard) header(offset uint64) (keyLen, valueLen int) {
+	var header [slabHeaderSize]byte
+	shard.read(offset, header[:])
+	return int(binary.LittleEndian.Uint32(header[8:])), int(binary.LittleEndian.Uint32(header[12:]))
+}
+
+// match checks the entry at offset holds key
+func (shard *slabShard) match(offset uint64, key string) bool {
+	keyLen, _ := shard.header(offset)
+	if keyLen != len(key) {
+		return false
+	}
+	first, second := shard.slice(offset+slabHeaderSize, keyLen)
+	return string(first) == key[:len(first)] && string(second) == key[len(first):]
+}
+
+// slice returns the ring bytes of [offset, offset+n), split in two when
+// they wrap around
+func (shard *slabShard) slice(offset uint64, n int) (first, second []byte) {
+	size := uint64(len(shard.ring))
+	position := offset % size
+	if position+uint64(n) <= size {
+		return shard.ring[position : position+uint64(n)], nil
+	}
+	return shard.ring[position:], shard.ring[:position+uint64(n)-size]
+}
+
+func (shard *slabShard) read(offset uint64, data []byte) {
+	first, second := shard.slice(offset, len(data))
+	copy(data[copy(data, first):], second)
+}
+
+func (shard *slabShard) write(offset uint64, data []byte) {
+	first, second := shard.slice(offset, len(data))
+	copy(second, data[copy(first, data):])
+}
@@ -0,0 +1,156 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SlabCacheTestSuite struct {
+	suite.Suite
+	cache *SlabCache
+}
+
+// slabEntrySize is the ring space taken by the entries of the tests
+const slabEntrySize = slabHeaderSize + 6 + 10
+
+func slabKey(i int) string {
+	return fmt.Sprintf("key-%02d", i)
+}
+
+func slabValue(i int) []byte {
+	return []byte(fmt.Sprintf("value-%04d", i))
+}
+
+func (s *SlabCacheTestSuite) SetupTest() {
+	s.cache = NewSlabCache(1, slabEntrySize*8)
+}
+
+func (s *SlabCacheTestSuite) TestSetGet() {
+	s.Nil(s.cache.Set(slabKey(1), slabValue(1)))
+	value, ok := s.cache.Get(slabKey(1))
+	s.True(ok)
+	s.Equal(slabValue(1), value)
+	_, ok = s.cache.Get(slabKey(2))
+	s.False(ok)
+
+	s.Nil(s.cache.Set(slabKey(1), []byte("updated")))
+	value, _ = s.cache.Get(slabKey(1))
+	s.Equal([]byte("updated"), value)
+	s.Equal(1, s.cache.Len())
+
+	s.True(s.cache.Remove(slabKey(1)))
+	s.False(s.cache.Remove(slabKey(1)))
+	s.Equal(0, s.cache.Len())
+
+	stats := s.cache.Stats()
+	s.Equal(uint64(2), stats.Hits)
+	s.Equal(uint64(1), stats.Misses)
+	s.Equal(uint64(1), stats.Inserts)
+	s.Equal(uint64(1), stats.Updates)
+	s.Equal(uint64(1), stats.Evictions[EvictReasonRemoved])
+}
+
+func (s *SlabCacheTestSuite) TestWraparound() {
+	for i := 0; i < 20; i++ {
+		s.Nil(s.cache.Set(slabKey(i), slabValue(i)))
+	}
+	// the ring holds the last 8 entries, the value bytes wrap around
+	s.Equal(8, s.cache.Len())
+	for i := 0; i < 20; i++ {
+		value, ok := s.cache.Get(slabKey(i))
+		s.Equal(i >= 12, ok, i)
+		if ok {
+			s.Equal(slabValue(i), value)
+		}
+	}
+	s.Equal(uint64(12), s.cache.Stats().Evictions[EvictReasonCapacity])
+
+	s.ErrorIs(s.cache.Set("big", make([]byte, slabEntrySize*8)), ErrEntryTooLarge)
+	s.Nil(s.cache.Set(slabKey(19), make([]byte, slabEntrySize*8)[:3]))
+	s.ErrorIs(s.cache.Set(slabKey(19), make([]byte, slabEntrySize*8)), ErrEntryTooLarge)
+	_, ok := s.cache.Get(slabKey(19))
+	s.False(ok)
+}
+
+func (s *SlabCacheTestSuite) TestApproximateLru() {
+	for i := 0; i < 8; i++ {
+		s.cache.Set(slabKey(i), slabValue(i))
+	}
+	// key 0 is read while in the older half, it is moved to the head
+	_, ok := s.cache.Get(slabKey(0))
+	s.True(ok)
+	for i := 8; i < 14; i++ {
+		s.cache.Set(slabKey(i), slabValue(i))
+	}
+	value, ok := s.cache.Get(slabKey(0))
+	s.True(ok)
+	s.Equal(slabValue(0), value)
+	_, ok = s.cache.Get(slabKey(1))
+	s.False(ok)
+}
+
+func (s *SlabCacheTestSuite) TestClear() {
+	cache := NewSlabCache(4, 1<<12)
+	for i := 0; i < 20; i++ {
+		cache.Set(slabKey(i), slabValue(i))
+	}
+	s.Equal(20, cache.Len())
+	cache.Clear()
+	s.Equal(0, cache.Len())
+	s.Equal(uint64(20), cache.Stats().Evictions[EvictReasonCleared])
+	_, ok := cache.Get(slabKey(1))
+	s.False(ok)
+	cache.ResetStats()
+	s.Equal(uint64(0), cache.Stats().Misses)
+}
+
+func (s *SlabCacheTestSuite) TestConcurrent() {
+	cache := NewSlabCache(4, 1<<12)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (i*7 + g) % 100
+				if value, ok := cache.Get(slabKey(key)); ok {
+					s.Equal(slabValue(key), value)
+				} else {
+					cache.Set(slabKey(key), slabValue(key))
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	s.LessOrEqual(cache.Len(), 100)
+}
+
+func TestSlabCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(SlabCacheTestSuite))
+}
+
+func BenchmarkSlabCacheParallel(b *testing.B) {
+	cache := NewSlabCache(64, benchmarkKeys/2*slabEntrySize)
+	keys := make([]string, benchmarkKeys)
+	for i := range keys {
+		keys[i] = slabKey(i)
+	}
+	value := slabValue(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[mix64(uint64(i))%benchmarkKeys]
+			if i%4 == 0 {
+				cache.Set(key, value)
+			} else {
+				cache.Get(key)
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package lru
+
+// DefaultSlruProtectedRatio is the share of capacity for the protected segment
+const DefaultSlruProtectedRatio = 0.8
+
+type slruEntry[v any] struct {
+	value     v
+	protected bool
+}
+
+// Slru implements a non-thread-safe segmented lru. New entries go to the
+// probationary segment, a hit moves them to the protected segment whose
+// overflow is demoted back to probation. Only probation entries are evicted
+// while it is not empty, so a scan cannot flush the protected entries.
+type Slru[k comparable, v any] struct {
+	capacity      int
+	protectedSize int
+
+	probation *List[k, slruEntry[v]]
+	protected *List[k, slruEntry[v]]
+	hash      map[k]*Node[k, slruEntry[v]]
+
+	onEvict EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*Slru[int, int])(nil)
+
+// NewSlru returns a slru holding at most capacity entries, capacity is at
+// least 1. protectedRatio sizes the protected segment relative to capacity,
+// ratios outside (0, 1] fall back to DefaultSlruProtectedRatio.
+func NewSlru[k comparable, v any](capacity int, protectedRatio float64, onEvict EvictFunc[k, v]) *Slru[k, v] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if protectedRatio <= 0 || protectedRatio > 1 {
+		protectedRatio = DefaultSlruProtectedRatio
+	}
+	slru := &Slru[k, v]{
+		capacity:      capacity,
+		protectedSize: int(float64(capacity) * protectedRatio),
+		onEvict:       onEvict,
+	}
+	slru.reset()
+	return slru
+}
+
+func (slru *Slru[k, v]) reset() {
+	slru.probation = NewList[k, slruEntry[v]]()
+	slru.protected = NewList[k, slruEntry[v]]()
+	slru.hash = make(map[k]*Node[k, slruEntry[v]])
+}
+
+func (slru *Slru[k, v]) Add(key k, value v) (overwrite bool) {
+	if node, ok := slru.hash[key]; ok {
+		node.value.value = value
+		slru.touch(node)
+		return true
+	}
+	if len(slru.hash) >= slru.capacity {
+		slru.removeNode(slru.victim(), EvictReasonCapacity)
+	}
+	slru.hash[key] = slru.probation.Prepend(key, slruEntry[v]{value: value})
+	return false
+}
+
+func (slru *Slru[k, v]) Get(key k) (value v, exist bool) {
+	if node, ok := slru.hash[key]; ok {
+		slru.touch(node)
+		return node.value.value, true
+	}
+	return value, false
+}
+
+func (slru *Slru[k, v]) Peek(key k) (value v, exist bool) {
+	if node, ok := slru.hash[key]; ok {
+		return node.value.value, true
+	}
+	return value, false
+}
+
+func (slru *Slru[k, v]) Remove(key k) (exist bool) {
+	if node, ok := slru.hash[key]; ok {
+		slru.removeNode(node, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+func (slru *Slru[k, v]) RemoveOldest() (key k, value v) {
+	node := slru.victim()
+	if node == nil {
+		return
+	}
+	slru.removeNode(node, EvictReasonRemoved)
+	return node.key, node.value.value
+}
+
+func (slru *Slru[k, v]) Clear() {
+	probation, protected := slru.probation, slru.protected
+	slru.reset()
+	if slru.onEvict != nil {
+		for _, list := range []*List[k, slruEntry[v]]{probation, protected} {
+			list.Iterate(func(key k, entry slruEntry[v]) bool {
+				slru.onEvict(key, entry.value, EvictReasonCleared)
+				return false
+			})
+		}
+	}
+}
+
+func (slru *Slru[k, v]) Len() int {
+	return len(slru.hash)
+}
+
+func (slru *Slru[k, v]) Cap() int {
+	return slru.capacity
+}
+
+// Iterate walks the protected then the probation segment, each from the
+// most recently used
+func (slru *Slru[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	slru.iterate(false, iterateFunc, slru.protected, slru.probation)
+}
+
+// IterateList walks in eviction order
+func (slru *Slru[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	slru.iterate(true, iterateFunc, slru.probation, slru.protected)
+}
+
+func (slru *Slru[k, v]) iterate(reverse bool, iterateFunc IterateFunc[k, v], lists ...*List[k, slruEntry[v]]) {
+	for _, list := range lists {
+		stop := false
+		list.walk(reverse, func(node *Node[k, slruEntry[v]]) bool {
+			stop = iterateFunc(node.key, node.value.value)
+			return stop
+		})
+		if stop {
+			return
+		}
+	}
+}
+
+// touch moves a hit entry to the protected head, demoting the protected tail
+// to probation when the segment overflows
+func (slru *Slru[k, v]) touch(node *Node[k, slruEntry[v]]) {
+	if node.value.protected {
+		slru.protected.MoveToFront(node)
+		return
+	}
+	slru.probation.Remove(node)
+	node.value.protected = true
+	slru.protected.pushFront(node)
+	if slru.protected.Len() > slru.protectedSize {
+		demoted := slru.protected.Back()
+		slru.protected.Remove(demoted)
+		demoted.value.protected = false
+		slru.probation.pushFront(demoted)
+	}
+}
+
+func (slru *Slru[k, v]) victim() *Node[k, slruEntry[v]] {
+	if node := slru.probation.Back(); node != nil {
+		return node
+	}
+	return slru.protected.Back()
+}
+
+func (slru *Slru[k, v]) removeNode(node *Node[k, slruEntry[v]], reason EvictReason) {
+	if node.value.protected {
+		slru.protected.Remove(node)
+	} else {
+		slru.probation.Remove(node)
+	}
+	delete(slru.hash, node.key)
+	if slru.onEvict != nil {
+		slru.onEvict(node.key, node.value.value, reason)
+	}
+}
@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SlruTestSuite struct {
+	suite.Suite
+}
+
+func (s *SlruTestSuite) TestSegments() {
+	slru := NewSlru[int, int](4, 0.5, nil)
+	for i := 0; i < 4; i++ {
+		slru.Add(i, i)
+	}
+	slru.Get(0)
+	slru.Get(1)
+	slru.Get(2)
+	s.Equal(2, slru.protected.Len())
+	s.False(slru.hash[0].value.protected)
+
+	var keys []int
+	slru.IterateList(func(key int, value int) bool {
+		keys = append(keys, key)
+		return false
+	})
+	s.Equal([]int{3, 0, 1, 2}, keys)
+
+	for i := 100; i < 110; i++ {
+		slru.Add(i, i)
+	}
+	for i := 1; i < 3; i++ {
+		_, ok := slru.Peek(i)
+		s.True(ok)
+	}
+}
+
+func (s *SlruTestSuite) TestDemote() {
+	slru := NewSlru[int, int](4, 0.5, nil)
+	for i := 0; i < 3; i++ {
+		slru.Add(i, i)
+		slru.Get(i)
+	}
+	// the protected tail goes back to probation
+	s.Equal(2, slru.protected.Len())
+	s.False(slru.hash[0].value.protected)
+
+	key, _ := slru.RemoveOldest()
+	s.Equal(0, key)
+	key, _ = slru.RemoveOldest()
+	s.Equal(1, key)
+	s.Equal(1, slru.Len())
+}
+
+func TestSlruTestSuite(t *testing.T) {
+	suite.Run(t, new(SlruTestSuite))
+}
@@ -0,0 +1,170 @@
+package lru
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const snapshotVersion = 1
+
+type snapshotHeader struct {
+	Version int
+	Count   int
+}
+
+type snapshotEntry[k comparable, v any] struct {
+	Key   k
+	Value v
+	// TTL is the remaining time to live in nanoseconds, 0 means never expire
+	TTL int64 `json:",omitempty"`
+}
+
+// entries copies the live entries from the oldest to the newest
+func (lru *Lru[k, v]) entries() []snapshotEntry[k, v] {
+	now := lru.clock.Now().UnixNano()
+	entries := make([]snapshotEntry[k, v], 0, lru.list.Len())
+	lru.list.walk(true, func(node *Node[k, v]) bool {
+		if node.expire != 0 && now >= node.expire {
+			return false
+		}
+		entry := snapshotEntry[k, v]{Key: node.key, Value: node.value}
+		if node.expire != 0 {
+			entry.TTL = node.expire - now
+		}
+		entries = append(entries, entry)
+		return false
+	})
+	return entries
+}
+
+func writeSnapshot[k comparable, v any](w io.Writer, codec Codec, entries []snapshotEntry[k, v]) error {
+	encoder := codec.NewEncoder(w)
+	if err := encoder.Encode(snapshotHeader{Version: snapshotVersion, Count: len(entries)}); err != nil {
+		return err
+	}
+	for i := range entries {
+		if err := encoder.Encode(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSnapshot[k comparable, v any](r io.Reader, codec Codec) ([]snapshotEntry[k, v], error) {
+	decoder := codec.NewDecoder(r)
+	var header snapshotHeader
+	if err := decoder.Decode(&header); err != nil {
+		return nil, err
+	}
+	if header.Version != snapshotVersion {
+		return nil, fmt.Errorf("lru: unsupported snapshot version %d", header.Version)
+	}
+	if header.Count < 0 {
+		return nil, fmt.Errorf("lru: invalid snapshot entry count %d", header.Count)
+	}
+	// the count is not trusted for an allocation, a short snapshot fails on
+	// decoding instead
+	var entries []snapshotEntry[k, v]
+	for i := 0; i < header.Count; i++ {
+		var entry snapshotEntry[k, v]
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Snapshot writes the live entries to w from the oldest to the newest along
+// with their remaining ttl, tags and pins are not saved
+func (lru *Lru[k, v]) Snapshot(w io.Writer, codec Codec) error {
+	return writeSnapshot(w, codec, lru.entries())
+}
+
+// Restore adds the entries of a snapshot, so they become the most recently
+// used ones in their saved order. Entries already cached are kept and a
+// bounded lru evicts as usual and keeps the tags of the entries it already
+// holds. Nothing is added if the snapshot is broken.
+func (lru *Lru[k, v]) Restore(r io.Reader, codec Codec) error {
+	entries, err := readSnapshot[k, v](r, codec)
+	if err != nil {
+		return err
+	}
+	lru.restore(entries)
+	return nil
+}
+
+func (lru *Lru[k, v]) restore(entries []snapshotEntry[k, v]) {
+	for _, entry := range entries {
+		lru.AddWithTTL(entry.Key, entry.Value, time.Duration(entry.TTL))
+	}
+}
+
+// Snapshot copies the entries under the read lock and encodes them after
+// releasing it
+func (cache *SyncLru[k, v]) Snapshot(w io.Writer, codec Codec) error {
+	cache.lock.RLock()
+	entries := cache.lru.entries()
+	cache.lock.RUnlock()
+	return writeSnapshot(w, codec, entries)
+}
+
+func (cache *SyncLru[k, v]) Restore(r io.Reader, codec Codec) error {
+	entries, err := readSnapshot[k, v](r, codec)
+	if err != nil {
+		return err
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.lru.restore(entries)
+	return nil
+}
+
+// SnapshotToFile writes a snapshot to a temporary file next to path and
+// renames it over path, so readers never see a partial snapshot
+func (cache *SyncLru[k, v]) SnapshotToFile(path string, codec Codec) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	writer := bufio.NewWriter(file)
+	if err = cache.Snapshot(writer, codec); err == nil {
+		if err = writer.Flush(); err == nil {
+			err = file.Sync()
+		}
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// RestoreFromFile restores a snapshot written by SnapshotToFile
+func (cache *SyncLru[k, v]) RestoreFromFile(path string, codec Codec) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return cache.Restore(bufio.NewReader(file), codec)
+}
+
+// StartPeriodicSnapshot calls SnapshotToFile every interval until the
+// returned stop function is called, failures go to onError if not nil.
+// interval <= 0 starts nothing.
+func (cache *SyncLru[k, v]) StartPeriodicSnapshot(path string, interval time.Duration, codec Codec, onError func(err error)) (stop func()) {
+	return every(interval, func() {
+		if err := cache.SnapshotToFile(path, codec); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
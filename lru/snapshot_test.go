@@ -0,0 +1,123 @@
+package lru
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SnapshotTestSuite struct {
+	suite.Suite
+	clock *fakeClock
+	lru   *Lru[string, int]
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	s.clock = newFakeClock()
+	s.lru = NewLruWithCapacity[string, int](8, nil)
+	s.lru.SetClock(s.clock)
+	s.lru.Add("a", 1)
+	s.lru.AddWithTTL("b", 2, time.Minute)
+	s.lru.AddWithTTL("gone", 0, time.Second)
+	s.lru.Add("c", 3)
+	s.lru.Get("a")
+	s.clock.Advance(time.Second)
+}
+
+func (s *SnapshotTestSuite) keys(lru *Lru[string, int]) []string {
+	var keys []string
+	lru.IterateList(func(key string, value int) bool {
+		keys = append(keys, key)
+		return false
+	})
+	return keys
+}
+
+func (s *SnapshotTestSuite) TestRoundTrip() {
+	for _, codec := range []Codec{GobCodec, JSONCodec} {
+		var buffer bytes.Buffer
+		s.Nil(s.lru.Snapshot(&buffer, codec))
+
+		restored := NewLruWithCapacity[string, int](8, nil)
+		restored.SetClock(s.clock)
+		s.Nil(restored.Restore(&buffer, codec))
+		s.Equal([]string{"b", "c", "a"}, s.keys(restored))
+		value, ok := restored.Get("a")
+		s.True(ok)
+		s.Equal(1, value)
+
+		// the remaining ttl survives
+		s.clock.Advance(58 * time.Second)
+		s.True(restored.Contains("b"))
+		s.clock.Advance(time.Second)
+		s.False(restored.Contains("b"))
+		s.clock.Advance(-59 * time.Second)
+	}
+}
+
+func (s *SnapshotTestSuite) TestRestoreSmaller() {
+	var buffer bytes.Buffer
+	s.Nil(s.lru.Snapshot(&buffer, GobCodec))
+	restored := NewLruWithCapacity[string, int](2, nil)
+	s.Nil(restored.Restore(&buffer, GobCodec))
+	s.Equal([]string{"c", "a"}, s.keys(restored))
+}
+
+func (s *SnapshotTestSuite) TestBroken() {
+	var buffer bytes.Buffer
+	s.Nil(s.lru.Snapshot(&buffer, JSONCodec))
+	broken := bytes.NewReader(buffer.Bytes()[:buffer.Len()-10])
+
+	restored := NewLru[string, int]()
+	s.NotNil(restored.Restore(broken, JSONCodec))
+	s.Equal(0, restored.Len())
+
+	s.NotNil(restored.Restore(bytes.NewBufferString(`{"Version":2,"Count":0}`), JSONCodec))
+}
+
+func (s *SnapshotTestSuite) TestHostileHeader() {
+	restored := NewLru[string, int]()
+	s.NotNil(restored.Restore(bytes.NewBufferString(`{"Version":1,"Count":-1}`), JSONCodec))
+	// a huge count must fail on the missing entries, not on the allocation
+	s.NotNil(restored.Restore(bytes.NewBufferString(`{"Version":1,"Count":9223372036854775807}`+"\n"+`{"Key":"a","Value":1}`), JSONCodec))
+	s.Equal(0, restored.Len())
+}
+
+func (s *SnapshotTestSuite) TestFile() {
+	path := filepath.Join(s.T().TempDir(), "cache.snapshot")
+	cache := NewSyncLru[string, int](8, nil)
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	s.Nil(cache.SnapshotToFile(path, GobCodec))
+
+	restored := NewSyncLru[string, int](8, nil)
+	s.Nil(restored.RestoreFromFile(path, GobCodec))
+	s.Equal(2, restored.Len())
+
+	files, err := os.ReadDir(filepath.Dir(path))
+	s.Nil(err)
+	s.Equal(1, len(files))
+}
+
+func (s *SnapshotTestSuite) TestPeriodic() {
+	path := filepath.Join(s.T().TempDir(), "cache.snapshot")
+	cache := NewSyncLru[string, int](8, nil)
+	cache.Add("a", 1)
+
+	stop := cache.StartPeriodicSnapshot(path, time.Millisecond, JSONCodec, func(err error) {
+		s.Fail(err.Error())
+	})
+	defer stop()
+	s.Eventually(func() bool {
+		restored := NewSyncLru[string, int](8, nil)
+		return restored.RestoreFromFile(path, JSONCodec) == nil && restored.Len() == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestSnapshotTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
@@ -0,0 +1,142 @@
+package lru
+
+import "sync/atomic"
+
+// Stats is a snapshot of cache counters
+type Stats struct {
+	Hits    uint64
+	Misses  uint64
+	Inserts uint64
+	Updates uint64
+	// Evictions counts entries that left the cache, indexed by EvictReason,
+	// e.g. Evictions[EvictReasonExpired] is the number of expirations
+	Evictions [evictReasonCount]uint64
+	// Size is the entry count when the snapshot was taken
+	Size int
+	// Pinned is the pinned entry count when the snapshot was taken
+	Pinned int
+}
+
+// HitRatio returns hits / (hits + misses), 0 before any lookup
+func (stats Stats) HitRatio() float64 {
+	total := stats.Hits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(total)
+}
+
+// Evicted sums evictions of every reason
+func (stats Stats) Evicted() uint64 {
+	var total uint64
+	for _, count := range stats.Evictions {
+		total += count
+	}
+	return total
+}
+
+func (stats *Stats) add(other Stats) {
+	stats.Hits += other.Hits
+	stats.Misses += other.Misses
+	stats.Inserts += other.Inserts
+	stats.Updates += other.Updates
+	for i := range stats.Evictions {
+		stats.Evictions[i] += other.Evictions[i]
+	}
+	stats.Size += other.Size
+	stats.Pinned += other.Pinned
+}
+
+// statsCounter is updated with atomics so lock-free readers may count hits
+// and Stats never needs the cache lock
+type statsCounter struct {
+	hits      uint64
+	misses    uint64
+	inserts   uint64
+	updates   uint64
+	evictions [evictReasonCount]uint64
+}
+
+func (counter *statsCounter) hit() {
+	atomic.AddUint64(&counter.hits, 1)
+}
+
+func (counter *statsCounter) miss() {
+	atomic.AddUint64(&counter.misses, 1)
+}
+
+func (counter *statsCounter) insert() {
+	atomic.AddUint64(&counter.inserts, 1)
+}
+
+func (counter *statsCounter) update() {
+	atomic.AddUint64(&counter.updates, 1)
+}
+
+func (counter *statsCounter) evict(reason EvictReason, count int) {
+	atomic.AddUint64(&counter.evictions[reason], uint64(count))
+}
+
+func (counter *statsCounter) snapshot() Stats {
+	stats := Stats{
+		Hits:    atomic.LoadUint64(&counter.hits),
+		Misses:  atomic.LoadUint64(&counter.misses),
+		Inserts: atomic.LoadUint64(&counter.inserts),
+		Updates: atomic.LoadUint64(&counter.updates),
+	}
+	for i := range counter.evictions {
+		stats.Evictions[i] = atomic.LoadUint64(&counter.evictions[i])
+	}
+	return stats
+}
+
+func (counter *statsCounter) reset() {
+	atomic.StoreUint64(&counter.hits, 0)
+	atomic.StoreUint64(&counter.misses, 0)
+	atomic.StoreUint64(&counter.inserts, 0)
+	atomic.StoreUint64(&counter.updates, 0)
+	for i := range counter.evictions {
+		atomic.StoreUint64(&counter.evictions[i], 0)
+	}
+}
+
+func (lru *Lru[k, v]) Stats() Stats {
+	stats := lru.stats.snapshot()
+	stats.Size = lru.Len()
+	stats.Pinned = lru.Pinned()
+	return stats
+}
+
+// ResetStats zeroes the counters, Size and Pinned are not counters and stay
+// accurate
+func (lru *Lru[k, v]) ResetStats() {
+	lru.stats.reset()
+}
+
+func (cache *SyncLru[k, v]) Stats() Stats {
+	stats := cache.lru.stats.snapshot()
+	cache.lock.RLock()
+	stats.Size = cache.lru.Len()
+	stats.Pinned = cache.lru.Pinned()
+	cache.lock.RUnlock()
+	return stats
+}
+
+func (cache *SyncLru[k, v]) ResetStats() {
+	cache.lru.ResetStats()
+}
+
+// Stats sums the stats of all shards
+func (cache *ShardedLru[k, v]) Stats() Stats {
+	var stats Stats
+	for _, shard := range cache.shards {
+		stats.add(shard.Stats())
+	}
+	return stats
+}
+
+func (cache *ShardedLru[k, v]) ResetStats() {
+	for _, shard := range cache.shards {
+		shard.ResetStats()
+	}
+}
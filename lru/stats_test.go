@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsTestSuite struct {
+	suite.Suite
+}
+
+func (s *StatsTestSuite) TestCounters() {
+	clock := newFakeClock()
+	lru := NewLruWithCapacity[int, string](2, nil)
+	lru.SetClock(clock)
+
+	lru.Add(1, "one")
+	lru.Add(1, "uno")
+	lru.AddWithTTL(2, "two", time.Second)
+	lru.Add(3, "three")
+	lru.Get(1)
+	lru.Get(3)
+	clock.Advance(time.Second)
+	lru.Add(4, "four")
+	lru.Get(4)
+	lru.Remove(4)
+	lru.Clear()
+
+	stats := lru.Stats()
+	s.Equal(uint64(2), stats.Hits)
+	s.Equal(uint64(1), stats.Misses)
+	s.Equal(uint64(4), stats.Inserts)
+	s.Equal(uint64(1), stats.Updates)
+	s.Equal(uint64(1), stats.Evictions[EvictReasonCapacity])
+	s.Equal(uint64(1), stats.Evictions[EvictReasonExpired])
+	s.Equal(uint64(1), stats.Evictions[EvictReasonRemoved])
+	s.Equal(uint64(1), stats.Evictions[EvictReasonCleared])
+	s.Equal(uint64(4), stats.Evicted())
+	s.InDelta(2.0/3, stats.HitRatio(), 0.0001)
+
+	lru.ResetStats()
+	s.Equal(Stats{}, lru.Stats())
+	s.Equal(float64(0), lru.Stats().HitRatio())
+}
+
+func (s *StatsTestSuite) TestExpirations() {
+	clock := newFakeClock()
+	cache := NewShardedLru[int, int](2, 0, nil, nil)
+	for _, shard := range cache.shards {
+		shard.SetClock(clock)
+	}
+	for i := 0; i < 10; i++ {
+		cache.AddWithTTL(i, i, time.Second)
+	}
+	clock.Advance(time.Second)
+	cache.Get(0)
+	cache.RemoveExpired()
+
+	stats := cache.Stats()
+	s.Equal(uint64(10), stats.Evictions[EvictReasonExpired])
+	s.Equal(uint64(1), stats.Misses)
+	s.Equal(0, stats.Size)
+}
+
+func (s *StatsTestSuite) TestMetricsHandler() {
+	users := NewSyncLru[string, string](10, nil)
+	users.Add("alice", "admin")
+	users.Get("alice")
+	users.Get("bob")
+
+	handler := NewMetricsHandler("")
+	handler.Register("users", users)
+	handler.Register(`we"ird`, NewLru[int, int]())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	s.True(strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+	s.Contains(body, "# TYPE lru_hits_total counter\n")
+	s.Contains(body, `lru_hits_total{cache="users"} 1`+"\n")
+	s.Contains(body, `lru_misses_total{cache="users"} 1`+"\n")
+	s.Contains(body, `lru_evictions_total{cache="users",reason="capacity"} 0`+"\n")
+	s.Contains(body, `lru_entries{cache="users"} 1`+"\n")
+	s.Contains(body, `lru_hit_ratio{cache="users"} 0.5`+"\n")
+	s.Contains(body, `lru_entries{cache="we\"ird"} 0`+"\n")
+
+	handler.Unregister("users")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	s.NotContains(recorder.Body.String(), "users")
+}
+
+func TestStatsTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsTestSuite))
+}
@@ -0,0 +1,111 @@
+package lru
+
+import "sync"
+
+// SyncLru wraps Lru with a lock so it can be shared between goroutines.
+// Get promotes the entry so it takes the write lock, use Peek for read-only
+// lookups. onEvict and iterate callbacks run with the lock held and must not
+// call back into the cache.
+type SyncLru[k comparable, v any] struct {
+	lock sync.RWMutex
+	lru  *Lru[k, v]
+}
+
+var _ ILru[int, int] = (*SyncLru[int, int])(nil)
+
+func NewSyncLru[k comparable, v any](capacity int, onEvict EvictFunc[k, v]) *SyncLru[k, v] {
+	return &SyncLru[k, v]{
+		lru: NewLruWithCapacity(capacity, onEvict),
+	}
+}
+
+func (cache *SyncLru[k, v]) Add(key k, value v) (overwrite bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Add(key, value)
+}
+
+func (cache *SyncLru[k, v]) Get(key k) (value v, exist bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Get(key)
+}
+
+// Peek returns the value of key without promoting it, only takes the read lock
+func (cache *SyncLru[k, v]) Peek(key k) (value v, exist bool) {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Peek(key)
+}
+
+func (cache *SyncLru[k, v]) Contains(key k) bool {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Contains(key)
+}
+
+// GetOrAdd returns the existing value of key if present, otherwise adds value.
+// loaded reports whether the value was already cached.
+func (cache *SyncLru[k, v]) GetOrAdd(key k, value v) (actual v, loaded bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if actual, loaded = cache.lru.Get(key); loaded {
+		return actual, true
+	}
+	cache.lru.Add(key, value)
+	return value, false
+}
+
+// ContainsOrAdd adds value only if key is absent, without promoting an
+// existing entry
+func (cache *SyncLru[k, v]) ContainsOrAdd(key k, value v) (exist bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.lru.Contains(key) {
+		return true
+	}
+	cache.lru.Add(key, value)
+	return false
+}
+
+func (cache *SyncLru[k, v]) Remove(key k) (exist bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Remove(key)
+}
+
+func (cache *SyncLru[k, v]) RemoveOldest() (key k, value v) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.RemoveOldest()
+}
+
+func (cache *SyncLru[k, v]) Clear() {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.lru.Clear()
+}
+
+func (cache *SyncLru[k, v]) Len() int {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Len()
+}
+
+func (cache *SyncLru[k, v]) Cap() int {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Cap()
+}
+
+func (cache *SyncLru[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	cache.lru.Iterate(iterateFunc)
+}
+
+func (cache *SyncLru[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	cache.lru.IterateList(iterateFunc)
+}
@@ -0,0 +1,71 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SyncLruTestSuite struct {
+	suite.Suite
+	cache *SyncLru[int, string]
+}
+
+func (s *SyncLruTestSuite) SetupTest() {
+	s.cache = NewSyncLru[int, string](16, nil)
+}
+
+func (s *SyncLruTestSuite) TestPeek() {
+	s.cache.Add(1, "one")
+	s.cache.Add(2, "two")
+
+	value, ok := s.cache.Peek(1)
+	s.True(ok)
+	s.Equal("one", value)
+	key, _ := s.cache.RemoveOldest()
+	s.Equal(1, key)
+}
+
+func (s *SyncLruTestSuite) TestGetOrAdd() {
+	actual, loaded := s.cache.GetOrAdd(1, "one")
+	s.False(loaded)
+	s.Equal("one", actual)
+
+	actual, loaded = s.cache.GetOrAdd(1, "uno")
+	s.True(loaded)
+	s.Equal("one", actual)
+
+	s.False(s.cache.ContainsOrAdd(2, "two"))
+	s.True(s.cache.ContainsOrAdd(2, "dos"))
+	value, _ := s.cache.Peek(2)
+	s.Equal("two", value)
+}
+
+// run with -race to validate the locking
+func (s *SyncLruTestSuite) TestConcurrent() {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := j % 32
+				actual, _ := s.cache.GetOrAdd(key, fmt.Sprintf("%v", key))
+				s.Equal(fmt.Sprintf("%v", key), actual)
+				s.cache.Peek(key)
+				s.cache.Get(key + 1)
+				if j%100 == id {
+					s.cache.Remove(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	s.LessOrEqual(s.cache.Len(), 16)
+}
+
+func TestSyncLruTestSuite(t *testing.T) {
+	suite.Run(t, new(SyncLruTestSuite))
+}
@@ -0,0 +1,144 @@
+package lru
+
+// AddWithTags works as Add and replaces the tags of the entry, a plain Add
+// keeps them. Tags let InvalidateTag remove related entries at once.
+func (lru *Lru[k, v]) AddWithTags(key k, value v, tags ...string) (overwrite bool) {
+	overwrite = lru.Add(key, value)
+	lru.untag(key)
+	lru.Tag(key, tags...)
+	return overwrite
+}
+
+// Tag adds tags to a cached entry, it returns false if key is absent. Tags
+// follow the entry until it leaves the cache or AddWithTags replaces them.
+func (lru *Lru[k, v]) Tag(key k, tags ...string) (exist bool) {
+	node, ok := lru.hash[key]
+	if !ok || lru.expired(node) {
+		return false
+	}
+	if len(tags) == 0 {
+		return true
+	}
+	if lru.tags == nil {
+		lru.tags = make(map[string]map[k]struct{})
+		lru.keyTags = make(map[k][]string)
+	}
+	for _, tag := range tags {
+		keys, ok := lru.tags[tag]
+		if !ok {
+			keys = make(map[k]struct{})
+			lru.tags[tag] = keys
+		}
+		if _, ok := keys[key]; !ok {
+			keys[key] = struct{}{}
+			lru.keyTags[key] = append(lru.keyTags[key], tag)
+		}
+	}
+	return true
+}
+
+// Tags returns the tags of key
+func (lru *Lru[k, v]) Tags(key k) []string {
+	return append([]string(nil), lru.keyTags[key]...)
+}
+
+// InvalidateTag removes every entry tagged with tag and returns how many
+// were removed, onEvict gets EvictReasonRemoved
+func (lru *Lru[k, v]) InvalidateTag(tag string) (removed int) {
+	for key := range lru.tags[tag] {
+		lru.removeNode(lru.hash[key], EvictReasonRemoved)
+		removed++
+	}
+	return removed
+}
+
+// RemoveIf removes every live entry matching predicate and returns how many
+// were removed, onEvict gets EvictReasonRemoved
+func (lru *Lru[k, v]) RemoveIf(predicate func(key k, value v) bool) (removed int) {
+	var matched []*Node[k, v]
+	now := lru.clock.Now().UnixNano()
+	lru.list.walk(false, func(node *Node[k, v]) bool {
+		if (node.expire == 0 || now < node.expire) && predicate(node.key, node.value) {
+			matched = append(matched, node)
+		}
+		return false
+	})
+	for _, node := range matched {
+		lru.removeNode(node, EvictReasonRemoved)
+	}
+	return len(matched)
+}
+
+// untag drops key from the tag indexes
+func (lru *Lru[k, v]) untag(key k) {
+	tags, ok := lru.keyTags[key]
+	if !ok {
+		return
+	}
+	for _, tag := range tags {
+		keys := lru.tags[tag]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(lru.tags, tag)
+		}
+	}
+	delete(lru.keyTags, key)
+}
+
+func (cache *SyncLru[k, v]) AddWithTags(key k, value v, tags ...string) (overwrite bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.AddWithTags(key, value, tags...)
+}
+
+func (cache *SyncLru[k, v]) Tag(key k, tags ...string) (exist bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.Tag(key, tags...)
+}
+
+func (cache *SyncLru[k, v]) Tags(key k) []string {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+	return cache.lru.Tags(key)
+}
+
+func (cache *SyncLru[k, v]) InvalidateTag(tag string) (removed int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.InvalidateTag(tag)
+}
+
+// RemoveIf runs predicate with the lock held
+func (cache *SyncLru[k, v]) RemoveIf(predicate func(key k, value v) bool) (removed int) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.RemoveIf(predicate)
+}
+
+func (cache *ShardedLru[k, v]) AddWithTags(key k, value v, tags ...string) (overwrite bool) {
+	return cache.shard(key).AddWithTags(key, value, tags...)
+}
+
+func (cache *ShardedLru[k, v]) Tag(key k, tags ...string) (exist bool) {
+	return cache.shard(key).Tag(key, tags...)
+}
+
+func (cache *ShardedLru[k, v]) Tags(key k) []string {
+	return cache.shard(key).Tags(key)
+}
+
+// InvalidateTag visits every shard, a tag may span all of them
+func (cache *ShardedLru[k, v]) InvalidateTag(tag string) (removed int) {
+	for _, shard := range cache.shards {
+		removed += shard.InvalidateTag(tag)
+	}
+	return removed
+}
+
+func (cache *ShardedLru[k, v]) RemoveIf(predicate func(key k, value v) bool) (removed int) {
+	for _, shard := range cache.shards {
+		removed += shard.RemoveIf(predicate)
+	}
+	return removed
+}
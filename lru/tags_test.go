@@ -0,0 +1,112 @@
+package lru
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TagsTestSuite struct {
+	suite.Suite
+	lru *Lru[string, string]
+}
+
+func (s *TagsTestSuite) SetupTest() {
+	s.lru = NewLruWithCapacity[string, string](8, nil)
+	s.lru.AddWithTags("profile:1", "alice", "user:1")
+	s.lru.AddWithTags("feed:1", "alice feed", "user:1", "feeds")
+	s.lru.AddWithTags("feed:2", "bob feed", "user:2", "feeds")
+	s.lru.Add("config", "on")
+}
+
+func (s *TagsTestSuite) TestInvalidateTag() {
+	s.Equal([]string{"user:1", "feeds"}, s.lru.Tags("feed:1"))
+	s.Equal(2, s.lru.InvalidateTag("user:1"))
+	s.False(s.lru.Contains("profile:1"))
+	s.False(s.lru.Contains("feed:1"))
+	s.Equal(0, s.lru.InvalidateTag("user:1"))
+
+	s.Equal(1, s.lru.InvalidateTag("feeds"))
+	s.Equal(1, s.lru.Len())
+	s.Equal(0, len(s.lru.tags))
+	s.Equal(0, len(s.lru.keyTags))
+}
+
+func (s *TagsTestSuite) TestIndexConsistency() {
+	// a plain Add keeps the tags, AddWithTags replaces them
+	s.lru.Add("feed:1", "alice feed v2")
+	s.Equal([]string{"user:1", "feeds"}, s.lru.Tags("feed:1"))
+	s.lru.AddWithTags("feed:1", "alice feed v3")
+	s.Nil(s.lru.Tags("feed:1"))
+	s.True(s.lru.Tag("feed:1", "user:1", "user:1"))
+	s.Equal([]string{"user:1"}, s.lru.Tags("feed:1"))
+	s.False(s.lru.Tag("missing", "user:1"))
+
+	// evictions and removals clean the indexes
+	s.lru.Resize(2)
+	s.Equal(map[string]struct{}{"feed:1": {}}, s.lru.tags["user:1"])
+	s.Nil(s.lru.tags["feeds"])
+	s.lru.Remove("feed:1")
+	s.Equal(0, s.lru.InvalidateTag("user:1"))
+	s.Equal(1, s.lru.Len())
+
+	s.lru.AddWithTags("a", "a", "t")
+	s.lru.Clear()
+	s.Equal(0, s.lru.InvalidateTag("t"))
+}
+
+func (s *TagsTestSuite) TestRestore() {
+	var buffer bytes.Buffer
+	s.Nil(s.lru.Snapshot(&buffer, JSONCodec))
+	snapshot := buffer.Bytes()
+
+	// snapshots do not carry tags
+	restored := NewLru[string, string]()
+	s.Nil(restored.Restore(bytes.NewReader(snapshot), JSONCodec))
+	s.Equal(4, restored.Len())
+	s.Nil(restored.Tags("feed:1"))
+	s.Equal(0, restored.InvalidateTag("feeds"))
+
+	// restoring over tagged entries keeps their tags
+	s.Nil(s.lru.Restore(bytes.NewReader(snapshot), JSONCodec))
+	s.Equal([]string{"user:1", "feeds"}, s.lru.Tags("feed:1"))
+	s.Equal(2, s.lru.InvalidateTag("feeds"))
+}
+
+func (s *TagsTestSuite) TestRemoveIf() {
+	clock := newFakeClock()
+	s.lru.SetClock(clock)
+	s.lru.AddWithTTL("feed:3", "carol feed", time.Second)
+	clock.Advance(time.Second)
+
+	removed := s.lru.RemoveIf(func(key string, value string) bool {
+		return strings.HasPrefix(key, "feed:")
+	})
+	s.Equal(2, removed)
+	s.Equal(3, s.lru.Len())
+	s.Nil(s.lru.tags["feeds"])
+	s.Equal(1, s.lru.RemoveExpired())
+}
+
+func (s *TagsTestSuite) TestSharded() {
+	cache := NewShardedLru[int, int](4, 0, nil, nil)
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			cache.AddWithTags(i, i, "even")
+		} else {
+			cache.Add(i, i)
+		}
+	}
+	s.Equal(10, cache.InvalidateTag("even"))
+	s.Equal(5, cache.RemoveIf(func(key int, value int) bool {
+		return value%4 == 1
+	}))
+	s.Equal(5, cache.Len())
+}
+
+func TestTagsTestSuite(t *testing.T) {
+	suite.Run(t, new(TagsTestSuite))
+}
@@ -0,0 +1,145 @@
+package lru
+
+import (
+	"os"
+	"sync"
+)
+
+// TieredCache keeps hot entries in a Lru and demotes the entries it evicts
+// for capacity to a DiskStore, a disk hit promotes the entry back to
+// memory. An entry lives in one tier at a time, so a value updated in
+// memory never meets an older copy on disk after a restart.
+type TieredCache struct {
+	lock   sync.Mutex
+	memory *Lru[string, []byte]
+	disk   *DiskStore
+	// demoteErr keeps the first error of the demotions run by an Add or Get
+	demoteErr error
+	closed    bool
+}
+
+// NewTieredCache returns a cache holding memoryCapacity entries in memory
+// on top of disk, the cache owns disk from now on
+func NewTieredCache(memoryCapacity int, disk *DiskStore) *TieredCache {
+	cache := &TieredCache{disk: disk}
+	cache.memory = NewLruWithCapacity(memoryCapacity, func(key string, value []byte, reason EvictReason) {
+		if reason != EvictReasonCapacity {
+			return
+		}
+		if err := cache.disk.Put(key, value); err != nil && cache.demoteErr == nil {
+			cache.demoteErr = err
+		}
+	})
+	return cache
+}
+
+// demoted returns and resets the demotion error
+func (cache *TieredCache) demoted() error {
+	err := cache.demoteErr
+	cache.demoteErr = nil
+	return err
+}
+
+// Add stores value in memory and drops an older copy from disk, the
+// returned error comes from the disk writes
+func (cache *TieredCache) Add(key string, value []byte) error {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.closed {
+		return os.ErrClosed
+	}
+	if _, err := cache.disk.Remove(key); err != nil {
+		return err
+	}
+	cache.memory.Add(key, value)
+	return cache.demoted()
+}
+
+// Get looks key up in memory then on disk, a disk hit is moved to memory.
+// err reports disk failures, including ErrCorruptRecord for a record
+// dropped because of its checksum.
+func (cache *TieredCache) Get(key string) (value []byte, exist bool, err error) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.closed {
+		return nil, false, os.ErrClosed
+	}
+	if value, ok := cache.memory.Get(key); ok {
+		return value, true, nil
+	}
+	value, ok, err := cache.disk.Get(key)
+	if !ok {
+		return nil, false, err
+	}
+	if _, err := cache.disk.Remove(key); err != nil {
+		return value, true, err
+	}
+	cache.memory.Add(key, value)
+	return value, true, cache.demoted()
+}
+
+func (cache *TieredCache) Remove(key string) (exist bool, err error) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.closed {
+		return false, os.ErrClosed
+	}
+	exist = cache.memory.Remove(key)
+	onDisk, err := cache.disk.Remove(key)
+	return exist || onDisk, err
+}
+
+func (cache *TieredCache) Clear() error {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.closed {
+		return os.ErrClosed
+	}
+	cache.memory.Clear()
+	return cache.disk.Clear()
+}
+
+// Len counts the entries of both tiers
+func (cache *TieredCache) Len() int {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.memory.Len() + cache.disk.Len()
+}
+
+// MemoryLen counts the entries held in memory
+func (cache *TieredCache) MemoryLen() int {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.memory.Len()
+}
+
+// Stats returns the stats of the memory tier, its misses include disk hits
+func (cache *TieredCache) Stats() Stats {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.memory.Stats()
+}
+
+// Close demotes the memory entries to disk so a later OpenDiskStore
+// recovers them, then closes the disk store. Add, Get, Remove and Clear
+// return os.ErrClosed afterwards, the cache reports no entry and a second
+// Close does nothing.
+func (cache *TieredCache) Close() error {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if cache.closed {
+		return nil
+	}
+	cache.closed = true
+	var err error
+	cache.memory.IterateList(func(key string, value []byte) bool {
+		err = cache.disk.Put(key, value)
+		return err != nil
+	})
+	cache.memory.Clear()
+	if err != nil {
+		cache.disk.Close()
+		return err
+	}
+	return cache.disk.Close()
+}
@@ -0,0 +1,110 @@
+package lru
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TieredCacheTestSuite struct {
+	suite.Suite
+	dir   string
+	cache *TieredCache
+}
+
+func (s *TieredCacheTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.cache = s.open()
+}
+
+func (s *TieredCacheTestSuite) TearDownTest() {
+	s.cache.Close()
+}
+
+func (s *TieredCacheTestSuite) open() *TieredCache {
+	disk, err := OpenDiskStore(s.dir, 0, 0)
+	s.Require().Nil(err)
+	return NewTieredCache(2, disk)
+}
+
+func tieredValue(i int) []byte {
+	return []byte(fmt.Sprintf("value-%v", i))
+}
+
+func (s *TieredCacheTestSuite) TestDemotePromote() {
+	for i := 0; i < 4; i++ {
+		s.Nil(s.cache.Add(fmt.Sprint(i), tieredValue(i)))
+	}
+	s.Equal(2, s.cache.MemoryLen())
+	s.Equal(4, s.cache.Len())
+	s.True(s.cache.disk.Contains("0"))
+
+	// the hit moves 0 to memory and demotes 2
+	value, ok, err := s.cache.Get("0")
+	s.Nil(err)
+	s.True(ok)
+	s.Equal(tieredValue(0), value)
+	s.False(s.cache.disk.Contains("0"))
+	s.True(s.cache.disk.Contains("2"))
+	s.Equal(4, s.cache.Len())
+
+	// an update drops the copy on disk
+	s.Nil(s.cache.Add("1", []byte("updated")))
+	s.False(s.cache.disk.Contains("1"))
+	value, _, _ = s.cache.Get("1")
+	s.Equal([]byte("updated"), value)
+
+	ok, err = s.cache.Remove("2")
+	s.True(ok)
+	s.Nil(err)
+	_, ok, _ = s.cache.Get("2")
+	s.False(ok)
+	s.Equal(3, s.cache.Len())
+}
+
+func (s *TieredCacheTestSuite) TestRestart() {
+	for i := 0; i < 4; i++ {
+		s.cache.Add(fmt.Sprint(i), tieredValue(i))
+	}
+	s.cache.Get("0")
+	s.Nil(s.cache.Close())
+
+	s.cache = s.open()
+	s.Equal(0, s.cache.MemoryLen())
+	s.Equal(4, s.cache.Len())
+	for i := 0; i < 4; i++ {
+		value, ok, err := s.cache.Get(fmt.Sprint(i))
+		s.Nil(err)
+		s.True(ok)
+		s.Equal(tieredValue(i), value)
+	}
+
+	s.Nil(s.cache.Clear())
+	s.Equal(0, s.cache.Len())
+}
+
+func (s *TieredCacheTestSuite) TestClosed() {
+	s.Nil(s.cache.Add("a", tieredValue(1)))
+	s.Nil(s.cache.Close())
+	s.Equal(0, s.cache.MemoryLen())
+	s.Equal(0, s.cache.Len())
+
+	s.ErrorIs(s.cache.Add("b", tieredValue(2)), os.ErrClosed)
+	value, ok, err := s.cache.Get("b")
+	s.Nil(value)
+	s.False(ok)
+	s.ErrorIs(err, os.ErrClosed)
+	_, _, err = s.cache.Get("a")
+	s.ErrorIs(err, os.ErrClosed)
+	_, err = s.cache.Remove("a")
+	s.ErrorIs(err, os.ErrClosed)
+	s.ErrorIs(s.cache.Clear(), os.ErrClosed)
+	s.Equal(0, s.cache.Len())
+	s.Nil(s.cache.Close())
+}
+
+func TestTieredCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(TieredCacheTestSuite))
+}
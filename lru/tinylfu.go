@@ -0,0 +1,170 @@
+package lru
+
+// TinyLfu implements a non-thread-safe W-TinyLFU cache. New entries land in
+// a small window lru, the entry leaving the window is a candidate for the
+// main Slru and only enters it if the frequency sketch rates it higher than
+// the main victim. The window absorbs bursts while the sketch keeps one-hit
+// wonders and scans out of the main space.
+type TinyLfu[k comparable, v any] struct {
+	capacity   int
+	windowSize int
+	window     *Lru[k, v]
+	main       *Slru[k, v]
+	sketch     *countMinSketch
+	hasher     Hasher[k]
+	onEvict    EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*TinyLfu[int, int])(nil)
+
+// NewTinyLfu returns a W-TinyLFU holding at most capacity entries, capacity
+// is at least 2. The window gets 1% of the capacity and the main Slru the
+// rest. hasher feeds the sketch and defaults to DefaultHasher when nil.
+func NewTinyLfu[k comparable, v any](capacity int, hasher Hasher[k], onEvict EvictFunc[k, v]) *TinyLfu[k, v] {
+	if capacity < 2 {
+		capacity = 2
+	}
+	if hasher == nil {
+		hasher = DefaultHasher[k]
+	}
+	windowSize := capacity / 100
+	if windowSize < 1 {
+		windowSize = 1
+	}
+	return &TinyLfu[k, v]{
+		capacity:   capacity,
+		windowSize: windowSize,
+		window:     NewLru[k, v](),
+		main:       NewSlru(capacity-windowSize, DefaultSlruProtectedRatio, onEvict),
+		sketch:     newCountMinSketch(capacity),
+		hasher:     hasher,
+		onEvict:    onEvict,
+	}
+}
+
+func (cache *TinyLfu[k, v]) Add(key k, value v) (overwrite bool) {
+	cache.sketch.Increment(cache.hasher(key))
+	if node, ok := cache.window.hash[key]; ok {
+		node.value = value
+		cache.window.list.MoveToFront(node)
+		return true
+	}
+	if node, ok := cache.main.hash[key]; ok {
+		node.value.value = value
+		cache.main.touch(node)
+		return true
+	}
+	cache.window.Add(key, value)
+	if cache.window.Len() > cache.windowSize {
+		cache.admit(cache.window.list.Back())
+	}
+	return false
+}
+
+// admit moves the window victim to the main space when it beats the main
+// victim in the sketch, otherwise evicts it
+func (cache *TinyLfu[k, v]) admit(candidate *Node[k, v]) {
+	cache.window.list.Remove(candidate)
+	delete(cache.window.hash, candidate.key)
+	if cache.main.Len() >= cache.main.Cap() {
+		victim := cache.main.victim()
+		if cache.sketch.Estimate(cache.hasher(candidate.key)) <= cache.sketch.Estimate(cache.hasher(victim.key)) {
+			if cache.onEvict != nil {
+				cache.onEvict(candidate.key, candidate.value, EvictReasonCapacity)
+			}
+			return
+		}
+		cache.main.removeNode(victim, EvictReasonCapacity)
+	}
+	cache.main.Add(candidate.key, candidate.value)
+}
+
+func (cache *TinyLfu[k, v]) Get(key k) (value v, exist bool) {
+	cache.sketch.Increment(cache.hasher(key))
+	if value, exist = cache.window.Get(key); exist {
+		return
+	}
+	return cache.main.Get(key)
+}
+
+// Peek returns the value of key without recording an access
+func (cache *TinyLfu[k, v]) Peek(key k) (value v, exist bool) {
+	if value, exist = cache.window.Peek(key); exist {
+		return
+	}
+	return cache.main.Peek(key)
+}
+
+func (cache *TinyLfu[k, v]) Remove(key k) (exist bool) {
+	if node, ok := cache.window.hash[key]; ok {
+		cache.removeWindowNode(node, EvictReasonRemoved)
+		return true
+	}
+	return cache.main.Remove(key)
+}
+
+// RemoveOldest pops the main victim, or the oldest window entry once the
+// main space is empty
+func (cache *TinyLfu[k, v]) RemoveOldest() (key k, value v) {
+	if cache.main.Len() > 0 {
+		return cache.main.RemoveOldest()
+	}
+	node := cache.window.list.Back()
+	if node == nil {
+		return
+	}
+	cache.removeWindowNode(node, EvictReasonRemoved)
+	return node.key, node.value
+}
+
+// Clear drops all entries but keeps the frequency history
+func (cache *TinyLfu[k, v]) Clear() {
+	window := cache.window
+	cache.window = NewLru[k, v]()
+	cache.main.Clear()
+	if cache.onEvict != nil {
+		window.Iterate(func(key k, value v) bool {
+			cache.onEvict(key, value, EvictReasonCleared)
+			return false
+		})
+	}
+}
+
+func (cache *TinyLfu[k, v]) Len() int {
+	return cache.window.Len() + cache.main.Len()
+}
+
+func (cache *TinyLfu[k, v]) Cap() int {
+	return cache.capacity
+}
+
+// Iterate walks the window then the main space
+func (cache *TinyLfu[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	stop := false
+	cache.window.Iterate(func(key k, value v) bool {
+		stop = iterateFunc(key, value)
+		return stop
+	})
+	if !stop {
+		cache.main.Iterate(iterateFunc)
+	}
+}
+
+// IterateList walks in the order RemoveOldest pops entries
+func (cache *TinyLfu[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	stop := false
+	cache.main.IterateList(func(key k, value v) bool {
+		stop = iterateFunc(key, value)
+		return stop
+	})
+	if !stop {
+		cache.window.IterateList(iterateFunc)
+	}
+}
+
+func (cache *TinyLfu[k, v]) removeWindowNode(node *Node[k, v], reason EvictReason) {
+	cache.window.removeNode(node, reason)
+	if cache.onEvict != nil {
+		cache.onEvict(node.key, node.value, reason)
+	}
+}
@@ -0,0 +1,79 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TinyLfuTestSuite struct {
+	suite.Suite
+}
+
+func (s *TinyLfuTestSuite) TestSketch() {
+	sketch := newCountMinSketch(64)
+	for i := 0; i < 5; i++ {
+		sketch.Increment(1)
+	}
+	sketch.Increment(2)
+	s.Equal(uint8(5), sketch.Estimate(1))
+	s.Equal(uint8(1), sketch.Estimate(2))
+	s.Equal(uint8(0), sketch.Estimate(3))
+
+	for i := 0; i < 100; i++ {
+		sketch.Increment(1)
+	}
+	s.LessOrEqual(sketch.Estimate(1), uint8(sketchMaxCounter))
+
+	// aging halves the counters
+	for i := uint64(100); sketch.additions > 0 && i < 1000; i++ {
+		sketch.Increment(i)
+	}
+	s.Less(sketch.Estimate(1), uint8(sketchMaxCounter))
+
+	sketch.Reset()
+	s.Equal(uint8(0), sketch.Estimate(1))
+}
+
+func (s *TinyLfuTestSuite) TestAdmission() {
+	cache := NewTinyLfu[int, int](4, nil, nil)
+	for i := 0; i < 4; i++ {
+		cache.Add(i, i)
+		cache.Get(i)
+		cache.Get(i)
+	}
+	s.Equal(4, cache.Len())
+
+	// cold keys are rejected instead of flushing the hot ones
+	for i := 100; i < 120; i++ {
+		cache.Add(i, i)
+	}
+	hits := 0
+	for i := 0; i < 4; i++ {
+		if _, ok := cache.Peek(i); ok {
+			hits++
+		}
+	}
+	s.GreaterOrEqual(hits, 3)
+	s.Equal(4, cache.Len())
+}
+
+func (s *TinyLfuTestSuite) TestZipfHitRatio() {
+	for seed := int64(1); seed <= 3; seed++ {
+		trace := zipfTrace(seed, traceCapacity*100, traceLength)
+		lru := hitRatio(NewLruWithCapacity[int, int](traceCapacity, nil), trace)
+		tinyLfu := hitRatio(NewTinyLfu[int, int](traceCapacity, nil, nil), trace)
+		s.Greater(tinyLfu, lru)
+	}
+}
+
+func (s *TinyLfuTestSuite) TestScanHitRatio() {
+	trace := scanTrace(1, traceLength)
+	lru := hitRatio(NewLruWithCapacity[int, int](traceCapacity, nil), trace)
+	tinyLfu := hitRatio(NewTinyLfu[int, int](traceCapacity, nil, nil), trace)
+	s.Greater(tinyLfu, lru+0.05)
+}
+
+func TestTinyLfuTestSuite(t *testing.T) {
+	suite.Run(t, new(TinyLfuTestSuite))
+}
@@ -0,0 +1,148 @@
+package lru
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the current time, replace SystemClock in tests to drive expiry
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock reads the wall clock
+var SystemClock Clock = systemClock{}
+
+// SetDefaultTTL sets the ttl given to entries by Add, ttl <= 0 means entries
+// never expire. Entries already cached keep their deadline.
+func (lru *Lru[k, v]) SetDefaultTTL(ttl time.Duration) {
+	lru.ttl = ttl
+}
+
+// SetClock replaces the clock used for expiry, nil restores SystemClock
+func (lru *Lru[k, v]) SetClock(clock Clock) {
+	if clock == nil {
+		clock = SystemClock
+	}
+	lru.clock = clock
+}
+
+// AddWithTTL works as Add but the entry expires after ttl, ttl <= 0 means
+// never
+func (lru *Lru[k, v]) AddWithTTL(key k, value v, ttl time.Duration) (overwrite bool) {
+	return lru.add(key, value, ttl)
+}
+
+// RemoveExpired drops every expired entry and returns how many were dropped
+func (lru *Lru[k, v]) RemoveExpired() int {
+	now := lru.clock.Now().UnixNano()
+	count := 0
+	for node := lru.list.head.nxt; node != lru.list.tail; {
+		next := node.nxt
+		if node.expire != 0 && now >= node.expire {
+			lru.removeNode(node, EvictReasonExpired)
+			count++
+		}
+		node = next
+	}
+	return count
+}
+
+func (lru *Lru[k, v]) deadline(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return lru.clock.Now().Add(ttl).UnixNano()
+}
+
+func (lru *Lru[k, v]) expired(node *Node[k, v]) bool {
+	return node.expire != 0 && lru.clock.Now().UnixNano() >= node.expire
+}
+
+func (cache *SyncLru[k, v]) SetDefaultTTL(ttl time.Duration) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.lru.SetDefaultTTL(ttl)
+}
+
+func (cache *SyncLru[k, v]) SetClock(clock Clock) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.lru.SetClock(clock)
+}
+
+func (cache *SyncLru[k, v]) AddWithTTL(key k, value v, ttl time.Duration) (overwrite bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.AddWithTTL(key, value, ttl)
+}
+
+func (cache *SyncLru[k, v]) RemoveExpired() int {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.lru.RemoveExpired()
+}
+
+// StartJanitor sweeps expired entries every interval in a background
+// goroutine until the returned stop function is called, interval <= 0 starts
+// nothing
+func (cache *SyncLru[k, v]) StartJanitor(interval time.Duration) (stop func()) {
+	return every(interval, func() {
+		cache.RemoveExpired()
+	})
+}
+
+// every runs fn every interval in a background goroutine until the returned
+// stop function is called. stop waits for a running fn and may be called
+// several times. interval <= 0 starts nothing and returns a no-op stop.
+func every(interval time.Duration, fn func()) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(exited)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fn()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+		<-exited
+	}
+}
+
+func (cache *ShardedLru[k, v]) SetDefaultTTL(ttl time.Duration) {
+	for _, shard := range cache.shards {
+		shard.SetDefaultTTL(ttl)
+	}
+}
+
+func (cache *ShardedLru[k, v]) AddWithTTL(key k, value v, ttl time.Duration) (overwrite bool) {
+	return cache.shard(key).AddWithTTL(key, value, ttl)
+}
+
+func (cache *ShardedLru[k, v]) RemoveExpired() int {
+	count := 0
+	for _, shard := range cache.shards {
+		count += shard.RemoveExpired()
+	}
+	return count
+}
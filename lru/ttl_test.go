@@ -0,0 +1,132 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeClock is a manually driven Clock
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1000, 0)}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(duration time.Duration) {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	clock.now = clock.now.Add(duration)
+}
+
+type TTLTestSuite struct {
+	suite.Suite
+	clock   *fakeClock
+	lru     *Lru[int, string]
+	expired []int
+}
+
+func (s *TTLTestSuite) SetupTest() {
+	s.clock = newFakeClock()
+	s.expired = nil
+	s.lru = NewLruWithCapacity(0, func(key int, value string, reason EvictReason) {
+		if reason == EvictReasonExpired {
+			s.expired = append(s.expired, key)
+		}
+	})
+	s.lru.SetClock(s.clock)
+}
+
+func (s *TTLTestSuite) TestAddWithTTL() {
+	s.lru.AddWithTTL(1, "one", time.Second)
+	s.lru.Add(2, "two")
+
+	s.clock.Advance(999 * time.Millisecond)
+	_, ok := s.lru.Get(1)
+	s.True(ok)
+
+	s.clock.Advance(time.Millisecond)
+	_, ok = s.lru.Peek(1)
+	s.False(ok)
+	s.False(s.lru.Contains(1))
+	s.Equal(2, s.lru.Len())
+
+	_, ok = s.lru.Get(1)
+	s.False(ok)
+	s.Equal(1, s.lru.Len())
+	s.Equal([]int{1}, s.expired)
+
+	_, ok = s.lru.Get(2)
+	s.True(ok)
+}
+
+func (s *TTLTestSuite) TestDefaultTTL() {
+	s.lru.SetDefaultTTL(time.Minute)
+	s.lru.Add(1, "one")
+	s.lru.AddWithTTL(2, "two", 0)
+	s.lru.Add(3, "three")
+
+	s.clock.Advance(30 * time.Second)
+	// overwrite resets the deadline
+	s.True(s.lru.Add(3, "three"))
+	s.clock.Advance(30 * time.Second)
+
+	var keys []int
+	s.lru.Iterate(func(key int, value string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	s.Equal([]int{3, 2}, keys)
+
+	s.Equal(1, s.lru.RemoveExpired())
+	s.Equal([]int{1}, s.expired)
+	s.Equal(2, s.lru.Len())
+}
+
+func (s *TTLTestSuite) TestAddExpired() {
+	s.lru.AddWithTTL(1, "one", time.Second)
+	s.clock.Advance(time.Second)
+	s.False(s.lru.Add(1, "uno"))
+	s.Equal([]int{1}, s.expired)
+
+	s.lru.Remove(1)
+	s.lru.AddWithTTL(2, "two", time.Second)
+	s.lru.Add(3, "three")
+	s.clock.Advance(time.Second)
+	key, _ := s.lru.RemoveOldest()
+	s.Equal(3, key)
+	s.Equal([]int{1, 2}, s.expired)
+}
+
+func (s *TTLTestSuite) TestJanitor() {
+	cache := NewSyncLru[int, string](0, nil)
+	cache.SetClock(s.clock)
+	cache.AddWithTTL(1, "one", time.Second)
+	cache.Add(2, "two")
+	s.clock.Advance(time.Second)
+
+	stop := cache.StartJanitor(time.Millisecond)
+	s.Eventually(func() bool {
+		return cache.Len() == 1
+	}, time.Second, time.Millisecond)
+	stop()
+	stop()
+
+	// no interval, no janitor
+	cache.StartJanitor(0)()
+}
+
+func TestTTLTestSuite(t *testing.T) {
+	suite.Run(t, new(TTLTestSuite))
+}
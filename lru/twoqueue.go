@@ -0,0 +1,217 @@
+package lru
+
+const (
+	// DefaultTwoQueueRecentRatio is the share of capacity for entries seen once
+	DefaultTwoQueueRecentRatio = 0.25
+	// DefaultTwoQueueGhostRatio is the size of the ghost queue relative to capacity
+	DefaultTwoQueueGhostRatio = 0.5
+)
+
+type twoQueueEntry[v any] struct {
+	value    v
+	frequent bool
+}
+
+// TwoQueue implements a non-thread-safe 2Q cache. New entries go to the
+// recent queue A1in, keys evicted from it are remembered in the ghost queue
+// A1out, and an entry hit again, or re-added while its key is a ghost, moves
+// to the frequent queue Am. A scan only churns A1in and leaves Am alone.
+type TwoQueue[k comparable, v any] struct {
+	capacity   int
+	recentSize int
+	ghostSize  int
+
+	recent   *List[k, twoQueueEntry[v]]
+	frequent *List[k, twoQueueEntry[v]]
+	hash     map[k]*Node[k, twoQueueEntry[v]]
+	ghost    *List[k, struct{}]
+	ghosts   map[k]*Node[k, struct{}]
+
+	onEvict EvictFunc[k, v]
+}
+
+var _ ILru[int, int] = (*TwoQueue[int, int])(nil)
+
+// NewTwoQueue returns a 2Q holding at most capacity entries, capacity is at
+// least 1. recentRatio sizes A1in and ghostRatio sizes A1out relative to
+// capacity, ratios outside (0, 1] fall back to the defaults.
+func NewTwoQueue[k comparable, v any](capacity int, recentRatio, ghostRatio float64, onEvict EvictFunc[k, v]) *TwoQueue[k, v] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if recentRatio <= 0 || recentRatio > 1 {
+		recentRatio = DefaultTwoQueueRecentRatio
+	}
+	if ghostRatio <= 0 || ghostRatio > 1 {
+		ghostRatio = DefaultTwoQueueGhostRatio
+	}
+	queue := &TwoQueue[k, v]{
+		capacity:   capacity,
+		recentSize: int(float64(capacity) * recentRatio),
+		ghostSize:  int(float64(capacity) * ghostRatio),
+		onEvict:    onEvict,
+	}
+	queue.reset()
+	return queue
+}
+
+func (queue *TwoQueue[k, v]) reset() {
+	queue.recent = NewList[k, twoQueueEntry[v]]()
+	queue.frequent = NewList[k, twoQueueEntry[v]]()
+	queue.hash = make(map[k]*Node[k, twoQueueEntry[v]])
+	queue.ghost = NewList[k, struct{}]()
+	queue.ghosts = make(map[k]*Node[k, struct{}])
+}
+
+func (queue *TwoQueue[k, v]) Add(key k, value v) (overwrite bool) {
+	if node, ok := queue.hash[key]; ok {
+		node.value.value = value
+		queue.touch(node)
+		return true
+	}
+	if ghost, ok := queue.ghosts[key]; ok {
+		queue.removeGhost(ghost)
+		queue.ensureSpace(true)
+		queue.hash[key] = queue.frequent.Prepend(key, twoQueueEntry[v]{value: value, frequent: true})
+		return false
+	}
+	queue.ensureSpace(false)
+	queue.hash[key] = queue.recent.Prepend(key, twoQueueEntry[v]{value: value})
+	return false
+}
+
+func (queue *TwoQueue[k, v]) Get(key k) (value v, exist bool) {
+	if node, ok := queue.hash[key]; ok {
+		queue.touch(node)
+		return node.value.value, true
+	}
+	return value, false
+}
+
+func (queue *TwoQueue[k, v]) Peek(key k) (value v, exist bool) {
+	if node, ok := queue.hash[key]; ok {
+		return node.value.value, true
+	}
+	return value, false
+}
+
+func (queue *TwoQueue[k, v]) Remove(key k) (exist bool) {
+	if ghost, ok := queue.ghosts[key]; ok {
+		queue.removeGhost(ghost)
+	}
+	if node, ok := queue.hash[key]; ok {
+		queue.removeNode(node, EvictReasonRemoved)
+		return true
+	}
+	return false
+}
+
+// RemoveOldest pops the entry 2Q would evict next, without remembering its key
+func (queue *TwoQueue[k, v]) RemoveOldest() (key k, value v) {
+	node := queue.victim(false)
+	if node == nil {
+		return
+	}
+	queue.removeNode(node, EvictReasonRemoved)
+	return node.key, node.value.value
+}
+
+func (queue *TwoQueue[k, v]) Clear() {
+	recent, frequent := queue.recent, queue.frequent
+	queue.reset()
+	if queue.onEvict != nil {
+		for _, list := range []*List[k, twoQueueEntry[v]]{recent, frequent} {
+			list.Iterate(func(key k, entry twoQueueEntry[v]) bool {
+				queue.onEvict(key, entry.value, EvictReasonCleared)
+				return false
+			})
+		}
+	}
+}
+
+func (queue *TwoQueue[k, v]) Len() int {
+	return len(queue.hash)
+}
+
+func (queue *TwoQueue[k, v]) Cap() int {
+	return queue.capacity
+}
+
+// Iterate walks Am then A1in, each from the most recently used
+func (queue *TwoQueue[k, v]) Iterate(iterateFunc IterateFunc[k, v]) {
+	queue.iterate(false, iterateFunc, queue.frequent, queue.recent)
+}
+
+// IterateList walks in eviction order
+func (queue *TwoQueue[k, v]) IterateList(iterateFunc IterateFunc[k, v]) {
+	first := queue.victim(false)
+	if first != nil && first.value.frequent {
+		queue.iterate(true, iterateFunc, queue.frequent, queue.recent)
+		return
+	}
+	queue.iterate(true, iterateFunc, queue.recent, queue.frequent)
+}
+
+func (queue *TwoQueue[k, v]) iterate(reverse bool, iterateFunc IterateFunc[k, v], lists ...*List[k, twoQueueEntry[v]]) {
+	for _, list := range lists {
+		stop := false
+		list.walk(reverse, func(node *Node[k, twoQueueEntry[v]]) bool {
+			stop = iterateFunc(node.key, node.value.value)
+			return stop
+		})
+		if stop {
+			return
+		}
+	}
+}
+
+// touch moves a hit entry to the head of Am
+func (queue *TwoQueue[k, v]) touch(node *Node[k, twoQueueEntry[v]]) {
+	if node.value.frequent {
+		queue.frequent.MoveToFront(node)
+		return
+	}
+	queue.recent.Remove(node)
+	node.value.frequent = true
+	queue.frequent.pushFront(node)
+}
+
+// victim picks A1in once it outgrows its share, Am otherwise
+func (queue *TwoQueue[k, v]) victim(ghostHit bool) *Node[k, twoQueueEntry[v]] {
+	recentLen := queue.recent.Len()
+	if recentLen > 0 && (recentLen > queue.recentSize || (recentLen == queue.recentSize && !ghostHit) || queue.frequent.Len() == 0) {
+		return queue.recent.Back()
+	}
+	return queue.frequent.Back()
+}
+
+func (queue *TwoQueue[k, v]) ensureSpace(ghostHit bool) {
+	if len(queue.hash) < queue.capacity {
+		return
+	}
+	node := queue.victim(ghostHit)
+	queue.removeNode(node, EvictReasonCapacity)
+	if !node.value.frequent && queue.ghostSize > 0 {
+		queue.ghosts[node.key] = queue.ghost.Prepend(node.key, struct{}{})
+		if queue.ghost.Len() > queue.ghostSize {
+			queue.removeGhost(queue.ghost.Back())
+		}
+	}
+}
+
+func (queue *TwoQueue[k, v]) removeGhost(node *Node[k, struct{}]) {
+	queue.ghost.Remove(node)
+	delete(queue.ghosts, node.key)
+}
+
+func (queue *TwoQueue[k, v]) removeNode(node *Node[k, twoQueueEntry[v]], reason EvictReason) {
+	if node.value.frequent {
+		queue.frequent.Remove(node)
+	} else {
+		queue.recent.Remove(node)
+	}
+	delete(queue.hash, node.key)
+	if queue.onEvict != nil {
+		queue.onEvict(node.key, node.value.value, reason)
+	}
+}
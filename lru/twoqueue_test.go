@@ -0,0 +1,48 @@
+package lru
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoQueueTestSuite struct {
+	suite.Suite
+}
+
+func (s *TwoQueueTestSuite) TestGhostPromote() {
+	queue := NewTwoQueue[int, int](4, 0.5, 0.5, nil)
+	for i := 0; i < 5; i++ {
+		queue.Add(i, i)
+	}
+	_, ok := queue.Peek(0)
+	s.False(ok)
+	s.Equal(1, queue.ghost.Len())
+
+	// a ghost key coming back goes straight to Am
+	queue.Add(0, 0)
+	s.True(queue.hash[0].value.frequent)
+	s.Equal(1, queue.ghost.Len())
+	s.NotNil(queue.ghosts[1])
+}
+
+func (s *TwoQueueTestSuite) TestScanResistant() {
+	queue := NewTwoQueue[int, int](8, 0, 0, nil)
+	for i := 0; i < 4; i++ {
+		queue.Add(i, i)
+		queue.Get(i)
+	}
+	for i := 100; i < 200; i++ {
+		queue.Add(i, i)
+	}
+	for i := 0; i < 4; i++ {
+		_, ok := queue.Get(i)
+		s.True(ok)
+	}
+	s.Equal(8, queue.Len())
+	s.Equal(4, queue.ghost.Len())
+}
+
+func TestTwoQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoQueueTestSuite))
+}